@@ -0,0 +1,397 @@
+// Command evalbench runs a golden set of CV fixtures through the evaluation
+// pipeline and reports how the scores compare with the expected ranges and
+// with the previous run.
+//
+//	go run ./cmd/evalbench -fixtures cmd/evalbench/testdata -mode record
+//	go run ./cmd/evalbench -fixtures cmd/evalbench/testdata -mode replay
+//
+// Modes: live (the default) calls Gemini, record calls Gemini and stores
+// every response, replay answers from stored responses only (no API key
+// needed, record first).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/GazDuckington/go-gin/pkgs/contacts"
+	gemini "github.com/GazDuckington/go-gin/pkgs/genai"
+	"github.com/GazDuckington/go-gin/pkgs/skills"
+	"github.com/GazDuckington/go-gin/pkgs/utils"
+	"github.com/unidoc/unipdf/v4/common/license"
+)
+
+// Golden is the fixture manifest, golden.json in the fixtures directory.
+// AsOf (YYYY-MM-DD) pins "present" so computed experience stays stable.
+type Golden struct {
+	AsOf     string    `json:"as_of"`
+	Fixtures []Fixture `json:"fixtures"`
+}
+
+// Fixture is one CV with the score ranges we expect for it. Expect keys are
+// cv_match_rate, project_score, cv_scores.<rubric>, project_scores.<rubric>,
+// experience.total_years, experience.relevant_years, skill_match.score,
+// hybrid.score or hybrid.<scorer>. A job description or required skills make
+// the fixture apply to a job with the fixture's title.
+type Fixture struct {
+	Name           string                `json:"name"`
+	File           string                `json:"file"`
+	Title          string                `json:"title"`
+	Blind          bool                  `json:"blind"`
+	JobDescription string                `json:"job_description"`
+	RequiredSkills []string              `json:"required_skills"`
+	Expect         map[string][2]float64 `json:"expect"`
+}
+
+type Report struct {
+	GeneratedAt   time.Time `json:"generated_at"`
+	Mode          string    `json:"mode"`
+	Model         string    `json:"model"`
+	PromptVersion string    `json:"prompt_version"`
+	Passed        int       `json:"passed"`
+	Failed        int       `json:"failed"`
+	Errored       int       `json:"errored"`
+	Results       []Result  `json:"results"`
+}
+
+type Result struct {
+	Name     string             `json:"name"`
+	Scores   map[string]float64 `json:"scores,omitempty"`
+	Delta    map[string]float64 `json:"delta,omitempty"` // against the previous run
+	Pass     bool               `json:"pass"`
+	Failures []string           `json:"failures,omitempty"`
+	Error    string             `json:"error,omitempty"`
+}
+
+func main() {
+	fixturesDir := flag.String("fixtures", "cmd/evalbench/testdata", "directory with golden.json and CV files")
+	mode := flag.String("mode", "live", "live | record | replay")
+	recordings := flag.String("recordings", "", "recorded responses directory (default <fixtures>/recordings)")
+	out := flag.String("out", "evalbench-report.json", "where to write the report")
+	previous := flag.String("previous", "", "previous report to compare with (default: the existing -out file)")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+
+	if *recordings == "" {
+		*recordings = filepath.Join(*fixturesDir, "recordings")
+	}
+	if *previous == "" {
+		*previous = *out
+	}
+
+	gen, err := newGenerator(cfg, *mode, *recordings)
+	if err != nil {
+		cfg.Logger.Fatalf("evalbench: %v", err)
+	}
+
+	if cfg.UnidocKey != "" {
+		if err := license.SetMeteredKey(cfg.UnidocKey); err != nil {
+			cfg.Logger.Warnf("evalbench: unidoc license rejected: %v", err)
+		}
+	}
+	if pdfExtractor, err := utils.NewPDFExtractor(cfg.PDFExtractors, cfg.ExtractionMode); err != nil {
+		cfg.Logger.Warnf("evalbench: %v", err)
+	} else {
+		utils.RegisterExtractor(utils.MIMEPDF, pdfExtractor)
+	}
+	if cfg.SkillsTaxonomy != "" {
+		taxonomy, err := skills.Load(cfg.SkillsTaxonomy)
+		if err != nil {
+			cfg.Logger.Fatalf("evalbench: %v", err)
+		}
+		skills.SetDefault(taxonomy)
+	}
+
+	golden, err := loadGolden(filepath.Join(*fixturesDir, "golden.json"))
+	if err != nil {
+		cfg.Logger.Fatalf("evalbench: %v", err)
+	}
+
+	prev, err := loadReport(*previous)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		cfg.Logger.Warnf("evalbench: ignoring previous report: %v", err)
+	}
+
+	// fixtures have no stored embeddings, so the similarity scorer never applies
+	pipeline := service.NewEvaluationPipeline(cfg, gen, nil)
+	report := run(context.Background(), pipeline, *fixturesDir, golden, prev)
+	report.Mode = *mode
+
+	printReport(report)
+
+	if err := writeReport(*out, report); err != nil {
+		cfg.Logger.Fatalf("evalbench: %v", err)
+	}
+
+	if report.Failed > 0 || report.Errored > 0 {
+		os.Exit(1)
+	}
+}
+
+func newGenerator(cfg *config.Config, mode, recordings string) (gemini.Generator, error) {
+	switch mode {
+	case "replay":
+		// without recordings every fixture would error, so say why up front
+		entries, err := os.ReadDir(recordings)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("no recordings in %s, run -mode record first (make evalbench MODE=record)", recordings)
+		}
+		return gemini.NewReplayGenerator(recordings), nil
+	case "live", "record":
+		if err := gemini.Init(context.Background(), cfg); err != nil {
+			return nil, fmt.Errorf("cannot init gemini: %w", err)
+		}
+		if mode == "record" {
+			return gemini.NewRecordingGenerator(gemini.Live, recordings), nil
+		}
+		return gemini.Live, nil
+	default:
+		return nil, fmt.Errorf("unknown mode %q", mode)
+	}
+}
+
+func run(ctx context.Context, p *service.EvaluationPipeline, dir string, golden *Golden, prev *Report) *Report {
+	report := &Report{
+		GeneratedAt:   time.Now().UTC(),
+		Model:         gemini.EvalModel,
+		PromptVersion: gemini.PromptVersion,
+	}
+
+	prevScores := map[string]map[string]float64{}
+	if prev != nil {
+		for _, r := range prev.Results {
+			prevScores[r.Name] = r.Scores
+		}
+	}
+
+	var asOf time.Time
+	if golden.AsOf != "" {
+		asOf, _ = time.Parse("2006-01-02", golden.AsOf)
+	}
+
+	for _, f := range golden.Fixtures {
+		res := Result{Name: f.Name}
+
+		text, found, err := readFixture(filepath.Join(dir, f.File))
+		if err == nil {
+			var eval *dto.CVEvaluationResponse
+			eval, err = p.Evaluate(ctx, service.EvaluationInput{
+				CV: &dto.CVResponse{
+					ID:       f.Name,
+					Title:    f.Title,
+					Summary:  text,
+					Contacts: found,
+				},
+				Blind: f.Blind,
+				AsOf:  asOf,
+				Job:   fixtureJob(f),
+			})
+			if err == nil {
+				res.Scores = flattenScores(eval)
+			}
+		}
+
+		if err != nil {
+			res.Error = err.Error()
+			report.Errored++
+			report.Results = append(report.Results, res)
+			continue
+		}
+
+		res.Failures = checkExpectations(res.Scores, f.Expect)
+		res.Pass = len(res.Failures) == 0
+		if res.Pass {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+
+		if before, ok := prevScores[f.Name]; ok {
+			res.Delta = map[string]float64{}
+			for k, v := range res.Scores {
+				if b, ok := before[k]; ok {
+					res.Delta[k] = v - b
+				}
+			}
+		}
+
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+func fixtureJob(f Fixture) *dto.JobResponse {
+	if f.JobDescription == "" && len(f.RequiredSkills) == 0 {
+		return nil
+	}
+	return &dto.JobResponse{
+		Title:          f.Title,
+		Description:    f.JobDescription,
+		Blind:          f.Blind,
+		RequiredSkills: skills.Default().Normalize(f.RequiredSkills),
+	}
+}
+
+// readFixture extracts a fixture the way an upload is: its text plus the
+// contacts found in the text and the document's links.
+func readFixture(path string) (string, []dto.ContactResponse, error) {
+	mime, err := utils.DetectMIME(path, path)
+	if err != nil {
+		return "", nil, err
+	}
+	text, err := utils.ExtractText(path, mime)
+	if err != nil {
+		return "", nil, err
+	}
+	links, err := utils.ExtractLinks(path, mime)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var found []dto.ContactResponse
+	for _, c := range contacts.Extract(text, links) {
+		found = append(found, dto.ContactResponse{Kind: string(c.Kind), Value: c.Value, Source: c.Source})
+	}
+	return text, found, nil
+}
+
+// flattenScores turns an evaluation into the keys used by Fixture.Expect.
+func flattenScores(e *dto.CVEvaluationResponse) map[string]float64 {
+	scores := map[string]float64{
+		"cv_match_rate": e.CVMatchRate,
+		"project_score": e.ProjectScore,
+	}
+	for k, v := range e.CVScores {
+		scores["cv_scores."+k] = v
+	}
+	for k, v := range e.ProjectScores {
+		scores["project_scores."+k] = v
+	}
+	if c := e.ExperienceCheck; c != nil {
+		scores["experience.total_years"] = c.TotalYears
+		scores["experience.relevant_years"] = c.RelevantYears
+	}
+	if m := e.SkillMatch; m != nil {
+		scores["skill_match.score"] = m.Score
+	}
+	if h := e.Hybrid; h != nil {
+		scores["hybrid.score"] = h.Score
+		for _, c := range h.Contributions {
+			if !c.Skipped {
+				scores["hybrid."+c.Scorer] = c.Score
+			}
+		}
+	}
+	return scores
+}
+
+func checkExpectations(scores map[string]float64, expect map[string][2]float64) []string {
+	keys := make([]string, 0, len(expect))
+	for k := range expect {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var failures []string
+	for _, k := range keys {
+		want := expect[k]
+		got, ok := scores[k]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s missing", k))
+			continue
+		}
+		if got < want[0] || got > want[1] {
+			failures = append(failures, fmt.Sprintf("%s=%.2f outside [%.2f, %.2f]", k, got, want[0], want[1]))
+		}
+	}
+	return failures
+}
+
+func loadGolden(path string) (*Golden, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read golden set: %w", err)
+	}
+	var g Golden
+	if err := json.Unmarshal(b, &g); err != nil {
+		return nil, fmt.Errorf("invalid golden set %s: %w", path, err)
+	}
+	if g.AsOf != "" {
+		if _, err := time.Parse("2006-01-02", g.AsOf); err != nil {
+			return nil, fmt.Errorf("invalid as_of in %s: %w", path, err)
+		}
+	}
+	return &g, nil
+}
+
+func loadReport(path string) (*Report, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func writeReport(path string, r *Report) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+func printReport(r *Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "FIXTURE\tRESULT\tCV MATCH\tΔ\tPROJECT\tΔ\tNOTES\n")
+	for _, res := range r.Results {
+		status := "PASS"
+		notes := strings.Join(res.Failures, "; ")
+		switch {
+		case res.Error != "":
+			status, notes = "ERROR", res.Error
+		case !res.Pass:
+			status = "FAIL"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", res.Name, status,
+			score(res.Scores, "cv_match_rate"), delta(res.Delta, "cv_match_rate"),
+			score(res.Scores, "project_score"), delta(res.Delta, "project_score"),
+			notes)
+	}
+	w.Flush()
+	fmt.Printf("\nmodel=%s prompt=v%s mode=%s passed=%d failed=%d errored=%d\n",
+		r.Model, r.PromptVersion, r.Mode, r.Passed, r.Failed, r.Errored)
+}
+
+func score(m map[string]float64, k string) string {
+	if v, ok := m[k]; ok {
+		return fmt.Sprintf("%.2f", v)
+	}
+	return "-"
+}
+
+func delta(m map[string]float64, k string) string {
+	if v, ok := m[k]; ok {
+		return fmt.Sprintf("%+.2f", v)
+	}
+	return ""
+}
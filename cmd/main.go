@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -9,10 +10,15 @@ import (
 
 	database "github.com/GazDuckington/go-gin/db"
 	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/repository"
 	"github.com/GazDuckington/go-gin/internal/routes"
+	"github.com/GazDuckington/go-gin/internal/service"
 	gemini "github.com/GazDuckington/go-gin/pkgs/genai"
 	"github.com/GazDuckington/go-gin/pkgs/minio"
 	"github.com/GazDuckington/go-gin/pkgs/qdrant"
+	"github.com/GazDuckington/go-gin/pkgs/skills"
+	"github.com/GazDuckington/go-gin/pkgs/utils"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
 	"github.com/unidoc/unipdf/v4/common/license"
 )
 
@@ -26,10 +32,9 @@ func main() {
 		cfg.Logger.Info("database connected successfully")
 	}
 
-	if err := qdrant.Init(cfg); err != nil {
-		cfg.Logger.Warnf("Faiure initiating Qdrant client: %v", err)
-	} else {
-		cfg.Logger.Info("qdrant client initialized")
+	store, err := newVectorStore(context.Background(), cfg)
+	if err != nil {
+		cfg.Logger.Fatalf("failed to set up VECTOR_STORE: %v", err)
 	}
 
 	if err := minio.Init(cfg); err != nil {
@@ -44,17 +49,48 @@ func main() {
 		cfg.Logger.Info("gemini client initialized")
 	}
 
-	err := license.SetMeteredKey(cfg.UnidocKey)
+	cache, err := service.NewEmbeddingCache(cfg, database.DB)
 	if err != nil {
+		cfg.Logger.Fatalf("failed to set up EMBEDDING_CACHE: %v", err)
+	}
+	if cache != nil {
+		gemini.SetEmbeddingCache(cache)
+		cfg.Logger.Infof("embedding cache: %s", cfg.EmbeddingCache)
+		// entries of a previous EMBEDDING_MODEL can never be hit again
+		if purged, err := cache.Invalidate(context.Background(), false); err != nil {
+			cfg.Logger.Warnf("failed to purge cached embeddings of other models: %v", err)
+		} else if purged > 0 {
+			cfg.Logger.Infof("purged %d cached embeddings of other models", purged)
+		}
+	}
+
+	if cfg.UnidocKey == "" {
+		cfg.Logger.Info("no unidoc key, PDFs fall back to the license-free extractor")
+	} else if err := license.SetMeteredKey(cfg.UnidocKey); err != nil {
 		cfg.Logger.Warnf("Faiure initiating unidoc license: %v", err)
 	} else {
 		cfg.Logger.Info("unidoc license accepted")
 	}
 
+	if pdfExtractor, err := utils.NewPDFExtractor(cfg.PDFExtractors, cfg.ExtractionMode); err != nil {
+		cfg.Logger.Warnf("invalid PDF_EXTRACTORS or EXTRACTION_MODE, using %s: %v", utils.DefaultPDFExtractors, err)
+	} else {
+		utils.RegisterExtractor(utils.MIMEPDF, pdfExtractor)
+		cfg.Logger.Infof("pdf extractors: %s", pdfExtractor.Name())
+	}
+
+	if cfg.SkillsTaxonomy != "" {
+		taxonomy, err := skills.Load(cfg.SkillsTaxonomy)
+		if err != nil {
+			cfg.Logger.Fatalf("failed to load SKILLS_TAXONOMY: %v", err)
+		}
+		skills.SetDefault(taxonomy)
+	}
+
 	// NOTE: we manage schema with migrate CLI; DO NOT call AutoMigrate here in prod.
 	// If you want to auto-migrate for quick dev, you can call it explicitly.
 
-	r := routes.SetupRouter(cfg)
+	r := routes.SetupRouter(cfg, store, cache)
 	addr := fmt.Sprintf(":%s", cfg.AppPort)
 	cfg.Logger.Infof("starting server on %s", addr)
 
@@ -70,3 +106,41 @@ func main() {
 	<-quit
 	cfg.Logger.Info("shutting down")
 }
+
+// newVectorStore returns the VECTOR_STORE backend. An unreachable Qdrant is
+// only logged, like the other clients, so the server still starts.
+func newVectorStore(ctx context.Context, cfg *config.Config) (vectorstore.VectorStore, error) {
+	switch cfg.VectorStore {
+	case "memory":
+		cfg.Logger.Warn("using the in-memory vector store, vectors are lost on restart")
+		return vectorstore.NewMemory(cfg.EmbeddingDim), nil
+	case "qdrant":
+		store, err := qdrant.New(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Init(ctx); errors.Is(err, qdrant.ErrReindexNeeded) {
+			cfg.Logger.Warnf("qdrant %v; run `make reindex` to move to %s", err, store.Collection())
+		} else if err != nil {
+			cfg.Logger.Warnf("failed to initialize Qdrant collection %s: %v", store.Collection(), err)
+		} else {
+			cfg.Logger.Info("qdrant client initialized")
+		}
+		return store, nil
+	case "pgvector":
+		if database.DB == nil {
+			return nil, errors.New("pgvector needs the database")
+		}
+		store := repository.NewCVVectorStore(database.DB, cfg)
+		if err := store.Init(ctx); errors.Is(err, vectorstore.ErrDimension) {
+			cfg.Logger.Warnf("pgvector %v; run `go run ./cmd/reindex -resize` to move to EMBEDDING_DIM", err)
+		} else if err != nil {
+			cfg.Logger.Warnf("pgvector store not ready: %v", err)
+		} else {
+			cfg.Logger.Info("pgvector store initialized")
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown vector store %q, use qdrant, pgvector or memory", cfg.VectorStore)
+	}
+}
@@ -0,0 +1,130 @@
+// Command reindex re-embeds every CV and its chunks with EMBEDDING_MODEL and
+// EMBEDDING_DIM.
+//
+//	go run ./cmd/reindex -batch 50 -checkpoint reindex-checkpoint.json
+//
+// With VECTOR_STORE=qdrant the vectors go into the versioned collection of
+// the model, <VECTOR_COLLECTION>_<model>_<dim>, while the server keeps reading
+// the old one through the VECTOR_COLLECTION alias. Once every CV is indexed
+// the alias is switched atomically; restart the server with the same
+// settings afterwards. With VECTOR_STORE=pgvector cvs.embedding and cv_chunks
+// are updated in place; their columns have a fixed size, so a new
+// EMBEDDING_DIM needs -resize, which drops every stored vector first. Progress
+// is checkpointed after every batch, so an interrupted run picks up where it
+// stopped.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	database "github.com/GazDuckington/go-gin/db"
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	"github.com/GazDuckington/go-gin/internal/service"
+	gemini "github.com/GazDuckington/go-gin/pkgs/genai"
+	"github.com/GazDuckington/go-gin/pkgs/qdrant"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+)
+
+func main() {
+	batch := flag.Int("batch", 50, "CVs per batch between checkpoints")
+	checkpoint := flag.String("checkpoint", "reindex-checkpoint.json", "where progress is saved")
+	switchAlias := flag.Bool("switch", true, "point the Qdrant alias at the new collection when done")
+	dropLegacy := flag.Bool("drop-legacy", false, "delete an unversioned collection named like the alias to free the name")
+	resize := flag.Bool("resize", false, "pgvector: change the vector columns to EMBEDDING_DIM, dropping every stored vector")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if *batch < 1 {
+		fmt.Fprintln(os.Stderr, "-batch must be at least 1")
+		os.Exit(2)
+	}
+	if err := database.Connect(cfg); err != nil {
+		cfg.Logger.Fatalf("database connection failed: %v", err)
+	}
+	if err := gemini.Init(ctx, cfg); err != nil {
+		cfg.Logger.Fatalf("failed to initialize Gemini client: %v", err)
+	}
+
+	cache, err := service.NewEmbeddingCache(cfg, database.DB)
+	if err != nil {
+		cfg.Logger.Fatalf("failed to set up EMBEDDING_CACHE: %v", err)
+	}
+	if cache != nil {
+		gemini.SetEmbeddingCache(cache)
+	}
+
+	var (
+		store  vectorstore.VectorStore
+		target string
+		alias  *qdrant.Store
+	)
+	switch cfg.VectorStore {
+	case "qdrant":
+		qs, err := qdrant.New(cfg)
+		if err != nil {
+			cfg.Logger.Fatalf("failed to connect to Qdrant: %v", err)
+		}
+		if err := qs.EnsureCollection(ctx); err != nil {
+			cfg.Logger.Fatalf("failed to create collection %s: %v", qs.Collection(), err)
+		}
+		store, target, alias = qs.Direct(), qs.Collection(), qs
+	case "pgvector":
+		pg := repository.NewCVVectorStore(database.DB, cfg)
+		err := pg.Init(ctx)
+		if errors.Is(err, vectorstore.ErrDimension) && *resize {
+			cfg.Logger.Warnf("%v; resizing, search finds nothing until the reindex is done", err)
+			if err := pg.Resize(ctx); err != nil {
+				cfg.Logger.Fatalf("failed to resize the vector columns: %v", err)
+			}
+			err = pg.Init(ctx)
+		}
+		if errors.Is(err, vectorstore.ErrDimension) {
+			cfg.Logger.Fatalf("%v; pgvector columns have a fixed size, run again with -resize to change them "+
+				"(every stored vector is dropped and search finds nothing until the reindex is done)", err)
+		}
+		if err != nil {
+			cfg.Logger.Fatalf("pgvector store not ready: %v", err)
+		}
+		store, target = pg, "cvs.embedding"
+	default:
+		cfg.Logger.Fatalf("cannot reindex VECTOR_STORE %q, use qdrant or pgvector", cfg.VectorStore)
+	}
+
+	cfg.Logger.Infof("reindexing into %s with %s (%d dimensions)", target, cfg.EmbeddingModel, cfg.EmbeddingDim)
+	reindexer := service.NewReindexer(cfg, repository.NewCVRepository(database.DB, cfg), store)
+	cp, err := reindexer.Run(ctx, target, *checkpoint, *batch)
+	if err != nil {
+		cfg.Logger.Fatalf("reindex stopped, run again to resume: %v", err)
+	}
+	cfg.Logger.Infof("reindex done: %d indexed, %d skipped, %d failed", cp.Indexed, cp.Skipped, len(cp.Failed))
+	if cache != nil {
+		st := cache.Stats()
+		cfg.Logger.Infof("embedding cache: %d hits, %d misses", st.MemoryHits+st.StoreHits, st.Misses)
+	}
+
+	if alias == nil || !*switchAlias {
+		return
+	}
+	if len(cp.Failed) > 0 {
+		cfg.Logger.Fatalf("not switching %s while %d CVs failed, run again to retry them", cfg.VectorCollection, len(cp.Failed))
+	}
+	previous, err := alias.SwitchAlias(ctx, *dropLegacy)
+	if err != nil {
+		cfg.Logger.Fatalf("failed to switch alias %s: %v", cfg.VectorCollection, err)
+	}
+	if previous == target {
+		cfg.Logger.Infof("alias %s already points at %s", cfg.VectorCollection, target)
+		return
+	}
+	cfg.Logger.Infof("alias %s now points at %s (was %q)", cfg.VectorCollection, target, previous)
+}
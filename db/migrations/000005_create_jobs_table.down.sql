@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_cvs_job_id;
+ALTER TABLE cvs DROP COLUMN IF EXISTS job_id;
+DROP TABLE IF EXISTS jobs;
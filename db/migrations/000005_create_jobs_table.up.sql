@@ -0,0 +1,13 @@
+CREATE TABLE jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title TEXT NOT NULL,
+    description TEXT,
+    blind BOOLEAN NOT NULL DEFAULT FALSE, -- evaluate applicants without identity attributes
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP NULL
+);
+
+ALTER TABLE cvs ADD COLUMN job_id UUID NULL REFERENCES jobs(id) ON DELETE SET NULL;
+CREATE INDEX idx_cvs_job_id ON cvs(job_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS human_scores;
+DROP TABLE IF EXISTS evaluations;
@@ -0,0 +1,30 @@
+CREATE TABLE evaluations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cv_id UUID NOT NULL REFERENCES cvs(id) ON DELETE CASCADE,
+    model TEXT NOT NULL,
+    prompt_version TEXT NOT NULL,
+    blind BOOLEAN NOT NULL DEFAULT FALSE,
+    cv_scores JSONB,
+    project_scores JSONB,
+    cv_match_rate DOUBLE PRECISION,
+    cv_feedback TEXT,
+    project_score DOUBLE PRECISION,
+    project_feedback TEXT,
+    overall_summary TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    deleted_at TIMESTAMP NULL
+);
+CREATE INDEX idx_evaluations_cv_id ON evaluations(cv_id);
+
+-- recruiter ground truth, one row per reviewer and rubric criterion
+CREATE TABLE human_scores (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    evaluation_id UUID NOT NULL REFERENCES evaluations(id) ON DELETE CASCADE,
+    reviewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    criterion TEXT NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (evaluation_id, reviewer_id, criterion)
+);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS evaluation_audits;
+DROP INDEX IF EXISTS idx_evaluations_review_status;
+ALTER TABLE evaluations
+    DROP COLUMN IF EXISTS review_status,
+    DROP COLUMN IF EXISTS reviewed_by,
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS override_cv_scores,
+    DROP COLUMN IF EXISTS override_project_scores,
+    DROP COLUMN IF EXISTS override_summary,
+    DROP COLUMN IF EXISTS override_reason;
@@ -0,0 +1,25 @@
+ALTER TABLE evaluations
+    ADD COLUMN review_status TEXT NOT NULL DEFAULT 'pending_review'
+        CHECK (review_status IN ('pending_review', 'approved', 'overridden')),
+    ADD COLUMN reviewed_by UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN reviewed_at TIMESTAMP NULL,
+    -- reviewer overrides live next to the untouched LLM output
+    ADD COLUMN override_cv_scores JSONB,
+    ADD COLUMN override_project_scores JSONB,
+    ADD COLUMN override_summary TEXT,
+    ADD COLUMN override_reason TEXT;
+
+CREATE INDEX idx_evaluations_review_status ON evaluations(review_status);
+
+CREATE TABLE evaluation_audits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    evaluation_id UUID NOT NULL REFERENCES evaluations(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action TEXT NOT NULL,
+    field TEXT,
+    old_value TEXT,
+    new_value TEXT,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_evaluation_audits_evaluation_id ON evaluation_audits(evaluation_id);
@@ -0,0 +1 @@
+ALTER TABLE cvs ADD COLUMN content_type TEXT NOT NULL DEFAULT 'application/pdf';
@@ -0,0 +1,2 @@
+ALTER TABLE cvs DROP COLUMN IF EXISTS extraction_quality;
+ALTER TABLE cvs DROP COLUMN IF EXISTS needs_ocr;
@@ -0,0 +1,2 @@
+ALTER TABLE cvs ADD COLUMN needs_ocr BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE cvs ADD COLUMN extraction_quality JSONB;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cv_contacts;
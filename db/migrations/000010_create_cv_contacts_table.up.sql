@@ -0,0 +1,11 @@
+-- normalized contact details and profile links found in a CV
+CREATE TABLE cv_contacts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cv_id UUID NOT NULL REFERENCES cvs(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    value TEXT NOT NULL,
+    source TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (cv_id, kind, value)
+);
+CREATE INDEX idx_cv_contacts_kind_value ON cv_contacts(kind, value);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS cv_educations;
+DROP TABLE IF EXISTS cv_experiences;
+DROP TABLE IF EXISTS cv_sections;
@@ -0,0 +1,37 @@
+-- structured CV content parsed from the extracted text
+CREATE TABLE cv_sections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cv_id UUID NOT NULL REFERENCES cvs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    kind TEXT NOT NULL,
+    heading TEXT,
+    content TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_cv_sections_cv_id ON cv_sections(cv_id);
+
+CREATE TABLE cv_experiences (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cv_id UUID NOT NULL REFERENCES cvs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    role TEXT,
+    employer TEXT,
+    start_date DATE,
+    end_date DATE,
+    is_current BOOLEAN NOT NULL DEFAULT FALSE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_cv_experiences_cv_id ON cv_experiences(cv_id);
+
+CREATE TABLE cv_educations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cv_id UUID NOT NULL REFERENCES cvs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    institution TEXT,
+    degree TEXT,
+    start_date DATE,
+    end_date DATE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_cv_educations_cv_id ON cv_educations(cv_id);
@@ -0,0 +1 @@
+ALTER TABLE evaluations ADD COLUMN experience_check JSONB;
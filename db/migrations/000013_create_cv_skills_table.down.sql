@@ -0,0 +1,3 @@
+ALTER TABLE evaluations DROP COLUMN IF EXISTS skill_match;
+ALTER TABLE jobs DROP COLUMN IF EXISTS required_skills;
+DROP TABLE IF EXISTS cv_skills;
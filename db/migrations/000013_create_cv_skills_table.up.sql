@@ -0,0 +1,14 @@
+-- canonical skills found in a CV, see pkgs/skills
+CREATE TABLE cv_skills (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cv_id UUID NOT NULL REFERENCES cvs(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    category TEXT,
+    mentions INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (cv_id, name)
+);
+CREATE INDEX idx_cv_skills_name ON cv_skills(name);
+
+ALTER TABLE jobs ADD COLUMN required_skills JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE evaluations ADD COLUMN skill_match JSONB;
@@ -0,0 +1 @@
+ALTER TABLE evaluations DROP COLUMN IF EXISTS hybrid;
@@ -0,0 +1,2 @@
+-- blended LLM and rule-based score with each scorer's contribution
+ALTER TABLE evaluations ADD COLUMN hybrid JSONB;
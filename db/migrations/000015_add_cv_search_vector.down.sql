@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_cvs_search_vector;
+ALTER TABLE cvs DROP COLUMN IF EXISTS search_vector;
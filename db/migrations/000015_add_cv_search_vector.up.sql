@@ -0,0 +1,9 @@
+-- full-text index over the CV title and text for keyword search. The 'simple'
+-- configuration does no stemming, so exact terms and codes like "AZ-104"
+-- match as written in English and Indonesian CVs alike.
+ALTER TABLE cvs ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(summary, '')), 'B')
+    ) STORED;
+CREATE INDEX idx_cvs_search_vector ON cvs USING GIN (search_vector);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_cvs_embedding;
+ALTER TABLE cvs ALTER COLUMN embedding TYPE JSONB USING embedding::text::jsonb;
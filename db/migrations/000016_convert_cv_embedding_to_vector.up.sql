@@ -0,0 +1,43 @@
+-- store CV embeddings as pgvector vectors so Postgres alone can serve vector
+-- search (VECTOR_STORE=pgvector). Needs the pgvector extension, see compose.yml.
+CREATE EXTENSION IF NOT EXISTS vector;
+
+ALTER TABLE cvs ADD COLUMN embedding_vector vector(768);
+
+-- rows that already hold a plain embedding
+UPDATE cvs SET embedding_vector = embedding::text::vector
+WHERE jsonb_typeof(embedding) = 'array' AND jsonb_array_length(embedding) = 768;
+
+-- older rows hold the bytes of the JSON encoded embedding read as little
+-- endian float32s. Turn each value back into its 4 bytes, join them into the
+-- JSON text and parse that. Rows that do not decode are left empty and get a
+-- vector again when the CV is re-embedded.
+DO $$
+DECLARE
+    r   RECORD;
+    raw BYTEA;
+BEGIN
+    FOR r IN
+        SELECT id, embedding FROM cvs
+        WHERE embedding_vector IS NULL
+          AND jsonb_typeof(embedding) = 'array' AND jsonb_array_length(embedding) > 0
+    LOOP
+        BEGIN
+            SELECT string_agg(
+                       set_byte(set_byte(set_byte(set_byte('\x00000000'::bytea,
+                           0, get_byte(b, 3)), 1, get_byte(b, 2)), 2, get_byte(b, 1)), 3, get_byte(b, 0)),
+                       ''::bytea ORDER BY e.ord)
+              INTO raw
+              FROM jsonb_array_elements_text(r.embedding) WITH ORDINALITY AS e(v, ord),
+                   LATERAL float4send(e.v::real) AS b;
+            UPDATE cvs SET embedding_vector = convert_from(raw, 'UTF8')::vector WHERE id = r.id;
+        EXCEPTION WHEN others THEN
+            RAISE NOTICE 'cv %: embedding could not be recovered: %', r.id, SQLERRM;
+        END;
+    END LOOP;
+END $$;
+
+ALTER TABLE cvs DROP COLUMN embedding;
+ALTER TABLE cvs RENAME COLUMN embedding_vector TO embedding;
+
+CREATE INDEX idx_cvs_embedding ON cvs USING hnsw (embedding vector_cosine_ops);
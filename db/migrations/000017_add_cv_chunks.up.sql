@@ -0,0 +1,14 @@
+-- one embedding per chunk of a CV's text, mostly a section, for chunk level
+-- search with VECTOR_STORE=pgvector. Filled when a CV is embedded; run
+-- cmd/reindex to chunk existing CVs.
+CREATE TABLE cv_chunks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cv_id UUID NOT NULL REFERENCES cvs(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    section TEXT NOT NULL,
+    embedding vector(768) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (cv_id, position)
+);
+
+CREATE INDEX idx_cv_chunks_embedding ON cv_chunks USING hnsw (embedding vector_cosine_ops);
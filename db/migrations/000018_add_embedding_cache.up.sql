@@ -0,0 +1,11 @@
+-- embeddings by SHA-256 of model, dimension and normalized text, so the same
+-- text is never embedded twice. The vector has no fixed size as entries of
+-- several models may be kept until they are purged.
+CREATE TABLE embedding_cache (
+    hash CHAR(64) PRIMARY KEY,
+    model TEXT NOT NULL,
+    dim INT NOT NULL,
+    embedding vector NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX idx_embedding_cache_model ON embedding_cache(model, dim);
@@ -29,6 +29,27 @@ type Config struct {
 	QdrantPort     int
 	QdrantGRPCPort int
 
+	// VectorStore is "qdrant", "pgvector" (cvs.embedding) or "memory"
+	// (brute force, lost on restart)
+	VectorStore string
+	// VectorCollection is the Qdrant alias in front of the collection of
+	// the current embedding model, see cmd/reindex
+	VectorCollection string
+	EmbeddingModel   string
+	EmbeddingDim     int
+
+	// chunked embeddings: CVs are embedded per section in pieces of at most
+	// ChunkMaxChars; vector search scores a CV by its best chunk ("max"), the
+	// mean of its ChunkTopK best ("mean") or its whole vector ("none")
+	ChunkMaxChars  int
+	ChunkAggregate string
+	ChunkTopK      int
+
+	// EmbeddingCache is "postgres" (embedding_cache table behind an LRU),
+	// "memory" (LRU only) or "off"; EmbeddingCacheSize is the LRU's entries
+	EmbeddingCache     string
+	EmbeddingCacheSize int
+
 	MinioUser   string
 	MinioPass   string
 	MinioPort   int
@@ -38,6 +59,51 @@ type Config struct {
 	GeminiKey string
 
 	UnidocKey string
+	// PDF extractors to try in order, e.g. "unipdf,native"
+	PDFExtractors string
+	// ExtractionMode is "plain" or "layout" (columns, bullets, tables as Markdown)
+	ExtractionMode string
+	// SkillsTaxonomy is a JSON or YAML skills file; empty uses the built-in one
+	SkillsTaxonomy string
+
+	// upload limits; 0 disables a limit
+	MaxUploadBytes int
+	MaxPDFPages    int
+
+	// extraction quality below these thresholds is treated as a scanned CV
+	QualityMinCharsPerPage    float64
+	QualityMinPrintableRatio  float64
+	QualityMinDictionaryRatio float64
+	QualityMaxEmptyPageRatio  float64
+	// LowQualityAction is "flag" (store, mark needs_ocr) or "reject"
+	LowQualityAction string
+	// OCRCommand is run on low quality PDFs when set, see utils.RunOCR
+	OCRCommand string
+	OCRTimeout time.Duration
+
+	// RedactionPolicy selects which PII is masked before text leaves the
+	// server: "none", "standard", "strict" or a list like "email,phone".
+	RedactionPolicy string
+
+	// hybrid CV search: reciprocal rank fusion weights and k, and how deep
+	// each side is ranked before fusing
+	SearchVectorWeight  float64
+	SearchKeywordWeight float64
+	SearchRRFK          float64
+	SearchMaxCandidates int
+
+	// job ranking: weights of evaluation, project and similarity, and the
+	// default shortlist cut-off
+	RankingWeights  string
+	RankingMinScore float64
+
+	// ScoreWeights blends the hybrid score, e.g. "llm=0.6,skills=0.2,experience=0.2"
+	ScoreWeights string
+
+	// thresholds for trusting the LLM evaluator against recruiter scores
+	CalibrationMinSamples int
+	CalibrationMinKappa   float64
+	CalibrationMaxMAE     float64
 
 	Logger *logrus.Logger
 }
@@ -88,7 +154,49 @@ func LoadConfig() *Config {
 		GeminiKey:      getEnv("GEMINI_API_KEY", ""),
 		MinioBucket:    "cvbucket",
 		UnidocKey:      getEnv("UNIDOC_KEY", ""),
-		Logger:         logger,
+		PDFExtractors:  getEnv("PDF_EXTRACTORS", "unipdf,native"),
+		ExtractionMode: getEnv("EXTRACTION_MODE", "plain"),
+		SkillsTaxonomy: getEnv("SKILLS_TAXONOMY", ""),
+		MaxUploadBytes: getEnv("MAX_UPLOAD_BYTES", 10<<20),
+		MaxPDFPages:    getEnv("MAX_PDF_PAGES", 10),
+
+		QualityMinCharsPerPage:    getEnv("QUALITY_MIN_CHARS_PER_PAGE", 200.0),
+		QualityMinPrintableRatio:  getEnv("QUALITY_MIN_PRINTABLE_RATIO", 0.9),
+		QualityMinDictionaryRatio: getEnv("QUALITY_MIN_DICTIONARY_RATIO", 0.15),
+		QualityMaxEmptyPageRatio:  getEnv("QUALITY_MAX_EMPTY_PAGE_RATIO", 0.5),
+		LowQualityAction:          getEnv("LOW_QUALITY_ACTION", "flag"),
+		OCRCommand:                getEnv("OCR_COMMAND", ""),
+		OCRTimeout:                time.Duration(getEnv("OCR_TIMEOUT_SECONDS", 120)) * time.Second,
+
+		RedactionPolicy: getEnv("REDACTION_POLICY", "standard"),
+
+		VectorStore:      getEnv("VECTOR_STORE", "qdrant"),
+		VectorCollection: getEnv("VECTOR_COLLECTION", "cvbucket"),
+		EmbeddingModel:   getEnv("EMBEDDING_MODEL", "text-embedding-004"),
+		EmbeddingDim:     getEnv("EMBEDDING_DIM", 768),
+
+		ChunkMaxChars:  getEnv("CHUNK_MAX_CHARS", 2000),
+		ChunkAggregate: getEnv("CHUNK_AGGREGATE", "max"),
+		ChunkTopK:      getEnv("CHUNK_TOP_K", 3),
+
+		EmbeddingCache:     getEnv("EMBEDDING_CACHE", "postgres"),
+		EmbeddingCacheSize: getEnv("EMBEDDING_CACHE_SIZE", 10000),
+
+		SearchVectorWeight:  getEnv("SEARCH_VECTOR_WEIGHT", 1.0),
+		SearchKeywordWeight: getEnv("SEARCH_KEYWORD_WEIGHT", 1.0),
+		SearchRRFK:          getEnv("SEARCH_RRF_K", 60.0),
+		SearchMaxCandidates: getEnv("SEARCH_MAX_CANDIDATES", 500),
+
+		RankingWeights:  getEnv("RANKING_WEIGHTS", "evaluation=0.6,project=0.1,similarity=0.3"),
+		RankingMinScore: getEnv("RANKING_MIN_SCORE", 0.0),
+
+		ScoreWeights: getEnv("SCORE_WEIGHTS", "llm=0.6,skills=0.2,similarity=0.1,experience=0.1"),
+
+		CalibrationMinSamples: getEnv("CALIBRATION_MIN_SAMPLES", 10),
+		CalibrationMinKappa:   getEnv("CALIBRATION_MIN_KAPPA", 0.6),
+		CalibrationMaxMAE:     getEnv("CALIBRATION_MAX_MAE", 0.75),
+
+		Logger: logger,
 	}
 
 	cfg.Logger.Debugf("Config loaded: env=%s port=%s db=%s", cfg.AppEnv, cfg.AppPort, cfg.DBName)
@@ -115,6 +223,12 @@ func getEnv[T any](key string, fallback T) T {
 			return fallback
 		}
 		return any(i).(T)
+	case float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fallback
+		}
+		return any(f).(T)
 	case string:
 		// remove accidental quotes or whitespace
 		value = strings.Trim(value, `"' `)
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/GazDuckington/go-gin/pkgs/embedcache"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController struct {
+	analytics service.AnalyticsService
+	cache     *embedcache.Cache
+	cfg       *config.Config
+}
+
+func NewAdminController(analytics service.AnalyticsService, cache *embedcache.Cache, cfg *config.Config) *AdminController {
+	return &AdminController{analytics: analytics, cache: cache, cfg: cfg}
+}
+
+// Clusters handles GET /admin/analytics/clusters?job_id=...&k=5&top=5
+func (ctrl *AdminController) Clusters(c *gin.Context) {
+	var q dto.ClusterQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clusters, err := ctrl.analytics.ClusterCVs(c.Request.Context(), q)
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("Clusters error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	if clusters == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": clusters})
+}
+
+// EmbeddingCacheStats handles GET /admin/embedding-cache
+func (ctrl *AdminController) EmbeddingCacheStats(c *gin.Context) {
+	if ctrl.cache == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "embedding cache is off"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": ctrl.cache.Stats()})
+}
+
+// InvalidateEmbeddingCache handles DELETE /admin/embedding-cache?all=true.
+// Without all only embeddings of other models are deleted.
+func (ctrl *AdminController) InvalidateEmbeddingCache(c *gin.Context) {
+	if ctrl.cache == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "embedding cache is off"})
+		return
+	}
+	deleted, err := ctrl.cache.Invalidate(c.Request.Context(), c.Query("all") == "true")
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("InvalidateEmbeddingCache error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"deleted": deleted}})
+}
@@ -1,12 +1,15 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/GazDuckington/go-gin/internal/config"
 	"github.com/GazDuckington/go-gin/internal/middleware"
 	"github.com/GazDuckington/go-gin/internal/models/dto"
 	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/GazDuckington/go-gin/pkgs/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 )
@@ -22,8 +25,18 @@ func NewCvController(s service.CVService, cfg *config.Config, wrk *service.CVWor
 }
 
 func (ctrl *CVController) SubmitCv(c *gin.Context) {
+	if limit := int64(ctrl.cfg.MaxUploadBytes); limit > 0 {
+		// leave room for the other form fields and multipart boundaries
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+1<<20)
+	}
+
 	var req dto.SubmitCvRequest
 	if err := c.ShouldBindWith(&req, binding.FormMultipart); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large", "code": utils.CodeFileTooLarge})
+			return
+		}
 		ctrl.cfg.Logger.Warnf("Create bind error: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -39,6 +52,11 @@ func (ctrl *CVController) SubmitCv(c *gin.Context) {
 	req.UserID = userClaims.UserID
 
 	submitted, err := ctrl.svc.SubmitCV(c, req)
+	if ue, ok := utils.AsUploadError(err); ok {
+		ctrl.cfg.Logger.Warnf("rejected cv upload: %v", ue)
+		c.JSON(ue.Status, gin.H{"error": ue.Message, "code": ue.Code})
+		return
+	}
 	if err != nil {
 		ctrl.cfg.Logger.Errorf("Error submitting cv: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
@@ -54,9 +72,17 @@ func (ctrl *CVController) GetCv(c *gin.Context) {
 		return
 	}
 
-	cv, err := ctrl.svc.GetCv(c.Request.Context(), cvID)
+	claims := c.MustGet("authClaims").(*middleware.Claims)
+	who := service.Searcher{UserID: claims.UserID, Admin: claims.Role == "admin"}
+
+	cv, err := ctrl.svc.GetCv(c.Request.Context(), who, cvID)
+	if errors.Is(err, service.ErrCVForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctrl.cfg.Logger.Errorf("GetCv error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
 		return
 	}
 	if cv == nil {
@@ -74,7 +100,17 @@ func (ctrl *CVController) EvaluateCv(c *gin.Context) {
 		return
 	}
 
-	status := ctrl.wrk.EnqueueCV(cvID)
+	blind := false
+	if v := c.Query("blind"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "blind must be a boolean"})
+			return
+		}
+		blind = b
+	}
+
+	status := ctrl.wrk.EnqueueCV(cvID, blind)
 
 	c.JSON(http.StatusOK, gin.H{"data": status})
 }
@@ -102,3 +138,56 @@ func (ctrl *CVController) EvaluationResult(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"data": status})
 }
+
+func (ctrl *CVController) GetAnonymized(c *gin.Context) {
+	cvID := c.Param("id")
+	if cvID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing CV ID"})
+		return
+	}
+
+	claims := c.MustGet("authClaims").(*middleware.Claims)
+	who := service.Searcher{UserID: claims.UserID, Admin: claims.Role == "admin"}
+
+	cv, err := ctrl.svc.GetAnonymized(c.Request.Context(), who, cvID)
+	if errors.Is(err, service.ErrCVForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("GetAnonymized error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	if cv == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "CV not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": cv})
+}
+
+// GetSimilar handles GET /cv/:id/similar?limit=10&job_id=&since=2025-01-01&until=&threshold=
+func (ctrl *CVController) GetSimilar(c *gin.Context) {
+	var q dto.SimilarCVsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	similar, err := ctrl.svc.GetSimilar(c.Request.Context(), c.Param("id"), q)
+	if errors.Is(err, service.ErrCVNoEmbedding) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("GetSimilar error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	if similar == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "CV not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": similar})
+}
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/middleware"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type EvaluationController struct {
+	svc service.EvaluationService
+	cfg *config.Config
+}
+
+func NewEvaluationController(s service.EvaluationService, cfg *config.Config) *EvaluationController {
+	return &EvaluationController{svc: s, cfg: cfg}
+}
+
+func (ctrl *EvaluationController) GetByID(c *gin.Context) {
+	eval, err := ctrl.svc.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("GetByID evaluation error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	if eval == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "evaluation not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": eval})
+}
+
+// SubmitHumanScores handles POST /evaluations/:id/human-scores
+func (ctrl *EvaluationController) SubmitHumanScores(c *gin.Context) {
+	var req dto.HumanScoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet("authClaims").(*middleware.Claims)
+
+	eval, err := ctrl.svc.SubmitHumanScores(c.Request.Context(), c.Param("id"), claims.UserID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidHumanScore) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctrl.cfg.Logger.Errorf("SubmitHumanScores error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	if eval == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "evaluation not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": eval})
+}
+
+// Calibration handles GET /admin/calibration
+func (ctrl *EvaluationController) Calibration(c *gin.Context) {
+	report, err := ctrl.svc.Calibration(c.Request.Context())
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("Calibration error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// List handles GET /evaluations?review_status=pending_review
+func (ctrl *EvaluationController) List(c *gin.Context) {
+	evals, err := ctrl.svc.List(c.Request.Context(), c.Query("review_status"))
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("List evaluations error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": evals})
+}
+
+// Approve handles POST /evaluations/:id/approve
+func (ctrl *EvaluationController) Approve(c *gin.Context) {
+	var req dto.ApproveEvaluationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	claims := c.MustGet("authClaims").(*middleware.Claims)
+
+	eval, err := ctrl.svc.Approve(c.Request.Context(), c.Param("id"), claims.UserID, req)
+	ctrl.respondReview(c, eval, err)
+}
+
+// Override handles POST /evaluations/:id/override
+func (ctrl *EvaluationController) Override(c *gin.Context) {
+	var req dto.OverrideEvaluationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet("authClaims").(*middleware.Claims)
+
+	eval, err := ctrl.svc.Override(c.Request.Context(), c.Param("id"), claims.UserID, req)
+	ctrl.respondReview(c, eval, err)
+}
+
+func (ctrl *EvaluationController) respondReview(c *gin.Context, eval *dto.EvaluationResponse, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidOverride):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrReviewConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		ctrl.cfg.Logger.Errorf("review error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+	case eval == nil:
+		c.JSON(http.StatusNotFound, gin.H{"error": "evaluation not found"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"data": eval})
+	}
+}
+
+// Audit handles GET /evaluations/:id/audit
+func (ctrl *EvaluationController) Audit(c *gin.Context) {
+	audits, err := ctrl.svc.Audit(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("Audit error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": audits})
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/middleware"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type JobController struct {
+	svc service.JobService
+	cfg *config.Config
+}
+
+func NewJobController(s service.JobService, cfg *config.Config) *JobController {
+	return &JobController{svc: s, cfg: cfg}
+}
+
+func (ctrl *JobController) GetAll(c *gin.Context) {
+	jobs, err := ctrl.svc.GetAll(c.Request.Context())
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("GetAll jobs error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": jobs})
+}
+
+func (ctrl *JobController) GetByID(c *gin.Context) {
+	job, err := ctrl.svc.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("GetByID job error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": job})
+}
+
+func (ctrl *JobController) Create(c *gin.Context) {
+	var req dto.CreateJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ctrl.cfg.Logger.Warnf("Create job bind error: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet("authClaims").(*middleware.Claims)
+
+	created, err := ctrl.svc.Create(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("Create job error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": created})
+}
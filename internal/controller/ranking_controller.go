@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RankingController struct {
+	svc service.RankingService
+	cfg *config.Config
+}
+
+func NewRankingController(s service.RankingService, cfg *config.Config) *RankingController {
+	return &RankingController{svc: s, cfg: cfg}
+}
+
+// RankJob handles GET /jobs/:id/ranking?min_score=0.6&top=10&format=csv
+func (ctrl *RankingController) RankJob(c *gin.Context) {
+	var q dto.JobRankingQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ranking, err := ctrl.svc.RankJob(c.Request.Context(), c.Param("id"), q)
+	if err != nil {
+		ctrl.cfg.Logger.Errorf("RankJob error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		return
+	}
+	if ranking == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	if q.Format == "csv" {
+		var buf bytes.Buffer
+		if err := service.WriteRankingCSV(&buf, ranking); err != nil {
+			ctrl.cfg.Logger.Errorf("RankJob csv error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="shortlist-%s.csv"`, ranking.JobID))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": ranking})
+}
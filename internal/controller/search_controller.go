@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/middleware"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchController struct {
+	svc service.SearchService
+	cfg *config.Config
+}
+
+func NewSearchController(s service.SearchService, cfg *config.Config) *SearchController {
+	return &SearchController{svc: s, cfg: cfg}
+}
+
+// SearchCVs handles POST /search/cvs
+func (ctrl *SearchController) SearchCVs(c *gin.Context) {
+	var req dto.SearchCVsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims := c.MustGet("authClaims").(*middleware.Claims)
+	who := service.Searcher{UserID: claims.UserID, Admin: claims.Role == "admin"}
+
+	res, err := ctrl.svc.SearchCVs(c.Request.Context(), who, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSearch):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrJobNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			ctrl.cfg.Logger.Errorf("SearchCVs error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
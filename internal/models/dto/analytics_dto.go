@@ -0,0 +1,53 @@
+package dto
+
+// ClusterQuery are the query parameters of GET /admin/analytics/clusters.
+// Without job_id the whole candidate pool is clustered; k = 0 picks the
+// number of clusters from the number of CVs.
+type ClusterQuery struct {
+	JobID string `form:"job_id" binding:"omitempty,uuid"`
+	K     int    `form:"k" binding:"omitempty,min=2,max=20"`
+	Top   int    `form:"top" binding:"omitempty,min=1,max=20"`
+}
+
+// TermCount is how many CVs of a cluster mention a skill or title.
+type TermCount struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// ClusterMember is a CV of a cluster with its similarity to the centroid.
+type ClusterMember struct {
+	CVID       string  `json:"cv_id"`
+	Title      string  `json:"title"`
+	Similarity float64 `json:"similarity"`
+}
+
+// CVCluster is a group of similar CVs. Label joins the most frequent skills;
+// Cohesion is the mean similarity of the members to the centroid. Averages
+// are over the members with an evaluation, on 0-1 with overrides applied,
+// and nil when none has one.
+type CVCluster struct {
+	ID              int             `json:"id"`
+	Label           string          `json:"label"`
+	Size            int             `json:"size"`
+	Share           float64         `json:"share"`
+	Cohesion        float64         `json:"cohesion"`
+	TopSkills       []TermCount     `json:"top_skills"`
+	TopTitles       []TermCount     `json:"top_titles"`
+	Evaluated       int             `json:"evaluated"`
+	AvgEvaluation   *float64        `json:"avg_evaluation"`
+	AvgProject      *float64        `json:"avg_project"`
+	Representatives []ClusterMember `json:"representatives"`
+}
+
+// ClusterAnalyticsResponse is the candidate pool, or a job's applicants,
+// clustered by CV embedding, largest cluster first. CVs without a stored
+// vector are counted in WithoutVector and left out.
+type ClusterAnalyticsResponse struct {
+	JobID         string      `json:"job_id,omitempty"`
+	JobTitle      string      `json:"job_title,omitempty"`
+	K             int         `json:"k"`
+	CVs           int         `json:"cvs"`
+	WithoutVector int         `json:"without_vector"`
+	Clusters      []CVCluster `json:"clusters"`
+}
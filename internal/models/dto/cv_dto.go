@@ -1,21 +1,72 @@
 package dto
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+
+	"github.com/GazDuckington/go-gin/pkgs/cvparse"
+	"github.com/GazDuckington/go-gin/pkgs/scoring"
+	"github.com/GazDuckington/go-gin/pkgs/skills"
+	"github.com/GazDuckington/go-gin/pkgs/utils"
+)
 
 type SubmitCvRequest struct {
 	UserID  string                `form:"user_id"`
+	JobID   string                `form:"job_id" binding:"omitempty,uuid"`
 	Title   string                `form:"title" binding:"required,max=150"`
 	File    *multipart.FileHeader `form:"file" binding:"required"`
 	Summary string                `form:"summary,omitempty"`
 }
 
 type CVResponse struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Title     string    `json:"title"`
-	FilePath  string    `json:"file_path"`
-	Summary   string    `json:"summary"`
-	Embedding []float32 `json:"embedding,omitempty"`
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Title       string    `json:"title"`
+	FilePath    string    `json:"file_path"`
+	ContentType string    `json:"content_type,omitempty"`
+	Summary     string    `json:"summary"`
+	Embedding   []float32 `json:"embedding,omitempty"`
+
+	NeedsOCR          bool              `json:"needs_ocr"`
+	ExtractionQuality *utils.Quality    `json:"extraction_quality,omitempty"`
+	Contacts          []ContactResponse `json:"contacts,omitempty"`
+
+	Sections    []SectionResponse    `json:"sections,omitempty"`
+	Experiences []ExperienceResponse `json:"experiences,omitempty"`
+	Educations  []EducationResponse  `json:"educations,omitempty"`
+	Skills      []skills.Skill       `json:"skills,omitempty"`
+
+	// Facts are computed statements handed to the evaluator as ground truth.
+	Facts []string `json:"-"`
+}
+
+// Dates in parsed entries are "YYYY-MM"; a year-only date is reported as
+// January (start) or December (end) of that year.
+type SectionResponse struct {
+	Kind    string `json:"kind"`
+	Heading string `json:"heading,omitempty"`
+	Content string `json:"content"`
+}
+
+type ExperienceResponse struct {
+	Role        string `json:"role"`
+	Employer    string `json:"employer"`
+	StartDate   string `json:"start_date,omitempty"`
+	EndDate     string `json:"end_date,omitempty"`
+	Current     bool   `json:"current"`
+	Description string `json:"description,omitempty"`
+}
+
+type EducationResponse struct {
+	Institution string `json:"institution"`
+	Degree      string `json:"degree"`
+	StartDate   string `json:"start_date,omitempty"`
+	EndDate     string `json:"end_date,omitempty"`
+}
+
+type ContactResponse struct {
+	Kind   string `json:"kind"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
 }
 
 type WorkerStatusResponse struct {
@@ -25,9 +76,25 @@ type WorkerStatusResponse struct {
 }
 
 type CVEvaluationResponse struct {
-	CVMatchRate     float64 `json:"cv_match_rate"`
-	CVFeedback      string  `json:"cv_feedback"`
-	ProjectScore    float64 `json:"project_score"`
-	ProjectFeedback string  `json:"project_feedback"`
-	OverallSummary  string  `json:"overall_summary"`
+	EvaluationID    string                   `json:"evaluation_id,omitempty"`
+	CVScores        map[string]float64       `json:"cv_scores,omitempty"`
+	ProjectScores   map[string]float64       `json:"project_scores,omitempty"`
+	CVMatchRate     float64                  `json:"cv_match_rate"`
+	CVFeedback      string                   `json:"cv_feedback"`
+	ProjectScore    float64                  `json:"project_score"`
+	ProjectFeedback string                   `json:"project_feedback"`
+	OverallSummary  string                   `json:"overall_summary"`
+	BlindMode       bool                     `json:"blind_mode"`
+	ExperienceCheck *cvparse.ExperienceCheck `json:"experience_check,omitempty"`
+	SkillMatch      *skills.MatchResult      `json:"skill_match,omitempty"`
+	Hybrid          *scoring.Result          `json:"hybrid,omitempty"`
+	ReviewStatus    string                   `json:"review_status,omitempty"`
+}
+
+// AnonymizedCVResponse is the CV text with identity attributes and contact
+// details removed, for reviewers working in blind mode.
+type AnonymizedCVResponse struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
 }
@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/GazDuckington/go-gin/pkgs/cvparse"
+	"github.com/GazDuckington/go-gin/pkgs/scoring"
+	"github.com/GazDuckington/go-gin/pkgs/skills"
+)
+
+// HumanScoresRequest carries a recruiter's own 1-5 score per rubric criterion.
+type HumanScoresRequest struct {
+	Scores map[string]float64 `json:"scores" binding:"required,min=1"`
+}
+
+type HumanScoreResponse struct {
+	ReviewerID string  `json:"reviewer_id"`
+	Criterion  string  `json:"criterion"`
+	Score      float64 `json:"score"`
+}
+
+type EvaluationResponse struct {
+	ID              string                   `json:"id"`
+	CVID            string                   `json:"cv_id"`
+	Model           string                   `json:"model"`
+	PromptVersion   string                   `json:"prompt_version"`
+	Blind           bool                     `json:"blind"`
+	CVScores        map[string]float64       `json:"cv_scores,omitempty"`
+	ProjectScores   map[string]float64       `json:"project_scores,omitempty"`
+	CVMatchRate     float64                  `json:"cv_match_rate"`
+	CVFeedback      string                   `json:"cv_feedback"`
+	ProjectScore    float64                  `json:"project_score"`
+	ProjectFeedback string                   `json:"project_feedback"`
+	OverallSummary  string                   `json:"overall_summary"`
+	ExperienceCheck *cvparse.ExperienceCheck `json:"experience_check,omitempty"`
+	SkillMatch      *skills.MatchResult      `json:"skill_match,omitempty"`
+	Hybrid          *scoring.Result          `json:"hybrid,omitempty"`
+	HumanScores     []HumanScoreResponse     `json:"human_scores,omitempty"`
+	ReviewStatus    string                   `json:"review_status"`
+	ReviewedBy      *string                  `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time               `json:"reviewed_at,omitempty"`
+	Override        *OverrideResponse        `json:"override,omitempty"`
+	CreatedAt       time.Time                `json:"created_at"`
+}
+
+// OverrideResponse is what a reviewer changed; the LLM output above is kept
+// as it was. CVMatchRate and ProjectScore are recomputed from the overridden
+// criteria.
+type OverrideResponse struct {
+	CVScores       map[string]float64 `json:"cv_scores,omitempty"`
+	ProjectScores  map[string]float64 `json:"project_scores,omitempty"`
+	CVMatchRate    float64            `json:"cv_match_rate"`
+	ProjectScore   float64            `json:"project_score"`
+	OverallSummary *string            `json:"overall_summary,omitempty"`
+	Reason         string             `json:"reason"`
+}
+
+type ApproveEvaluationRequest struct {
+	Comment string `json:"comment"`
+}
+
+// OverrideEvaluationRequest changes individual criterion scores and/or the
+// summary. Reason is mandatory.
+type OverrideEvaluationRequest struct {
+	CVScores       map[string]float64 `json:"cv_scores"`
+	ProjectScores  map[string]float64 `json:"project_scores"`
+	OverallSummary *string            `json:"overall_summary"`
+	Reason         string             `json:"reason" binding:"required,min=3"`
+}
+
+type EvaluationAuditResponse struct {
+	ID        string    `json:"id"`
+	ActorID   string    `json:"actor_id"`
+	Action    string    `json:"action"`
+	Field     string    `json:"field,omitempty"`
+	OldValue  string    `json:"old_value,omitempty"`
+	NewValue  string    `json:"new_value,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CalibrationMetrics compares LLM and recruiter scores. Metrics are nil when
+// they cannot be computed (too few samples or no variance).
+type CalibrationMetrics struct {
+	Criterion string   `json:"criterion,omitempty"`
+	Samples   int      `json:"samples"`
+	MAE       *float64 `json:"mae"`
+	Spearman  *float64 `json:"spearman"`
+	Kappa     *float64 `json:"kappa"`
+	Verdict   string   `json:"verdict"` // trusted, not_trusted, insufficient_data
+}
+
+type CalibrationGroup struct {
+	Model         string               `json:"model"`
+	PromptVersion string               `json:"prompt_version"`
+	Overall       CalibrationMetrics   `json:"overall"`
+	Criteria      []CalibrationMetrics `json:"criteria"`
+}
+
+type CalibrationThresholds struct {
+	MinSamples int     `json:"min_samples"`
+	MinKappa   float64 `json:"min_kappa"`
+	MaxMAE     float64 `json:"max_mae"`
+}
+
+type CalibrationResponse struct {
+	Thresholds CalibrationThresholds `json:"thresholds"`
+	Groups     []CalibrationGroup    `json:"groups"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+type CreateJobRequest struct {
+	Title       string `json:"title" binding:"required,max=150"`
+	Description string `json:"description"`
+	Blind       bool   `json:"blind"`
+	// RequiredSkills may use any synonym, they are stored by canonical name.
+	RequiredSkills []string `json:"required_skills" binding:"omitempty,max=50,dive,max=100"`
+}
+
+type JobResponse struct {
+	ID             string   `json:"id"`
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	Blind          bool     `json:"blind"`
+	RequiredSkills []string `json:"required_skills"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/GazDuckington/go-gin/pkgs/scoring"
+)
+
+// JobRankingQuery are the query parameters of GET /jobs/:id/ranking. An
+// applicant is shortlisted when their score reaches min_score and they are
+// within the first top (0 = no limit).
+type JobRankingQuery struct {
+	MinScore          *float64 `form:"min_score" binding:"omitempty,min=0,max=1"`
+	Top               int      `form:"top" binding:"omitempty,min=1"`
+	RequireEvaluation bool     `form:"require_evaluation"`
+	Format            string   `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// RankedCV is one applicant in a job ranking. Components are the weighted
+// parts of Score; evaluation and project scores include reviewer overrides.
+type RankedCV struct {
+	Rank         int                    `json:"rank"`
+	CVID         string                 `json:"cv_id"`
+	UserID       string                 `json:"user_id"`
+	Candidate    string                 `json:"candidate,omitempty"`
+	Title        string                 `json:"title"`
+	Score        float64                `json:"score"`
+	Components   []scoring.Contribution `json:"components"`
+	EvaluationID string                 `json:"evaluation_id,omitempty"`
+	ReviewStatus string                 `json:"review_status,omitempty"`
+	Overridden   bool                   `json:"overridden"`
+	Shortlisted  bool                   `json:"shortlisted"`
+	SubmittedAt  time.Time              `json:"submitted_at"`
+}
+
+type JobRankingResponse struct {
+	JobID       string             `json:"job_id"`
+	Title       string             `json:"title"`
+	Weights     map[string]float64 `json:"weights"`
+	MinScore    float64            `json:"min_score"`
+	Top         int                `json:"top,omitempty"`
+	Total       int                `json:"total"`
+	Shortlisted int                `json:"shortlisted"`
+	Results     []RankedCV         `json:"results"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import "time"
+
+// SearchCVsRequest finds CVs for either a free-text query or a job posting.
+// Threshold applies to the vector side; the weights override the configured
+// reciprocal rank fusion weights in hybrid mode.
+type SearchCVsRequest struct {
+	Query         string   `json:"query" binding:"max=2000"`
+	JobID         string   `json:"job_id" binding:"omitempty,uuid"`
+	Mode          string   `json:"mode" binding:"omitempty,oneof=hybrid vector keyword"`
+	Page          int      `json:"page" binding:"omitempty,min=1"`
+	PageSize      int      `json:"page_size" binding:"omitempty,min=1,max=100"`
+	Threshold     float64  `json:"threshold" binding:"omitempty,min=0,max=1"`
+	VectorWeight  *float64 `json:"vector_weight" binding:"omitempty,min=0"`
+	KeywordWeight *float64 `json:"keyword_weight" binding:"omitempty,min=0"`
+}
+
+// CVSearchHit is one ranked CV. Score is the fused score in hybrid mode, else
+// the cosine similarity (vector) or ts_rank_cd (keyword); ranks are 1-based
+// and 0 when that side did not find the CV.
+type CVSearchHit struct {
+	ID           string  `json:"id"`
+	UserID       string  `json:"user_id"`
+	Title        string  `json:"title"`
+	ContentType  string  `json:"content_type,omitempty"`
+	Score        float64 `json:"score"`
+	VectorScore  float64 `json:"vector_score,omitempty"`
+	VectorRank   int     `json:"vector_rank,omitempty"`
+	KeywordScore float64 `json:"keyword_score,omitempty"`
+	KeywordRank  int     `json:"keyword_rank,omitempty"`
+}
+
+type CVSearchResponse struct {
+	Results  []CVSearchHit `json:"results"`
+	Mode     string        `json:"mode"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+	HasMore  bool          `json:"has_more"`
+}
+
+// SimilarCVsQuery are the query parameters of GET /cv/:id/similar; dates are
+// YYYY-MM-DD upload dates, until is exclusive.
+type SimilarCVsQuery struct {
+	Limit     int       `form:"limit" binding:"omitempty,min=1,max=50"`
+	JobID     string    `form:"job_id" binding:"omitempty,uuid"`
+	Since     time.Time `form:"since" time_format:"2006-01-02"`
+	Until     time.Time `form:"until" time_format:"2006-01-02"`
+	Threshold float64   `form:"threshold" binding:"omitempty,min=0,max=1"`
+}
+
+// SimilarCV is a candidate like the source CV, with the cosine similarity.
+type SimilarCV struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	JobID     string     `json:"job_id,omitempty"`
+	Title     string     `json:"title"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Score     float64    `json:"score"`
+}
+
+type SimilarCVsResponse struct {
+	SourceID string      `json:"source_id"`
+	Results  []SimilarCV `json:"results"`
+}
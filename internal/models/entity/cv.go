@@ -3,19 +3,34 @@ package entity
 import (
 	"time"
 
+	"github.com/GazDuckington/go-gin/pkgs/utils"
 	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
 	"gorm.io/gorm"
 )
 
 type CV struct {
-	ID        string    `gorm:"type:uuid;primaryKey" json:"id"`
-	UserID    string    `gorm:"type:uuid;not null;index" json:"user_id"`
-	Title     string    `gorm:"size:150;not null" json:"title"`
-	FilePath  string    `gorm:"size:255;not null" json:"file_path"`
-	Summary   string    `gorm:"type:text" json:"summary"`
-	Embedding []float32 `gorm:"type:jsonb" json:"embedding"`
+	ID          string           `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      string           `gorm:"type:uuid;not null;index" json:"user_id"`
+	JobID       *string          `gorm:"type:uuid;index" json:"job_id"`
+	Title       string           `gorm:"size:150;not null" json:"title"`
+	FilePath    string           `gorm:"size:255;not null" json:"file_path"`
+	ContentType string           `gorm:"not null;default:application/pdf" json:"content_type"`
+	Summary     string           `gorm:"type:text" json:"summary"`
+	Embedding   *pgvector.Vector `gorm:"type:vector" json:"embedding"` // EMBEDDING_DIM values
+
+	// NeedsOCR marks a CV whose extracted text was too poor to evaluate.
+	NeedsOCR          bool           `gorm:"column:needs_ocr;not null;default:false" json:"needs_ocr"`
+	ExtractionQuality *utils.Quality `gorm:"type:jsonb;serializer:json" json:"extraction_quality,omitempty"`
 
 	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user"`
+	Job  *Job  `gorm:"foreignKey:JobID;constraint:OnDelete:SET NULL" json:"job,omitempty"`
+
+	Contacts    []CVContact    `gorm:"foreignKey:CVID" json:"contacts,omitempty"`
+	Sections    []CVSection    `gorm:"foreignKey:CVID" json:"sections,omitempty"`
+	Experiences []CVExperience `gorm:"foreignKey:CVID" json:"experiences,omitempty"`
+	Educations  []CVEducation  `gorm:"foreignKey:CVID" json:"educations,omitempty"`
+	Skills      []CVSkill      `gorm:"foreignKey:CVID" json:"skills,omitempty"`
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
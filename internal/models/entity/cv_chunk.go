@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+)
+
+// CVChunk is the embedding of one chunk of a CV's text, in document order.
+type CVChunk struct {
+	ID        string          `gorm:"type:uuid;primaryKey" json:"id"`
+	CVID      string          `gorm:"column:cv_id;type:uuid;not null;index" json:"cv_id"`
+	Position  int             `gorm:"not null" json:"position"`
+	Section   string          `gorm:"not null" json:"section"`
+	Embedding pgvector.Vector `gorm:"type:vector;not null" json:"embedding"` // EMBEDDING_DIM values
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CVChunk) TableName() string {
+	return "cv_chunks"
+}
+
+func (c *CVChunk) BeforeCreate(tx *gorm.DB) (err error) {
+	c.ID = uuid.NewString()
+	c.CreatedAt = time.Now()
+	return nil
+}
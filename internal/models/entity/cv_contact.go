@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CVContact is a normalized email, phone number or profile link from a CV.
+type CVContact struct {
+	ID     string `gorm:"type:uuid;primaryKey" json:"id"`
+	CVID   string `gorm:"column:cv_id;type:uuid;not null;index" json:"cv_id"`
+	Kind   string `gorm:"not null" json:"kind"`
+	Value  string `gorm:"not null" json:"value"`
+	Source string `gorm:"not null" json:"source"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CVContact) TableName() string {
+	return "cv_contacts"
+}
+
+func (c *CVContact) BeforeCreate(tx *gorm.DB) (err error) {
+	c.ID = uuid.NewString()
+	c.CreatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CVSection is one section of a parsed CV, in document order.
+type CVSection struct {
+	ID       string `gorm:"type:uuid;primaryKey" json:"id"`
+	CVID     string `gorm:"column:cv_id;type:uuid;not null;index" json:"cv_id"`
+	Position int    `gorm:"not null" json:"position"`
+	Kind     string `gorm:"not null" json:"kind"`
+	Heading  string `json:"heading"`
+	Content  string `gorm:"type:text" json:"content"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CVSection) TableName() string {
+	return "cv_sections"
+}
+
+func (s *CVSection) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.NewString()
+	s.CreatedAt = time.Now()
+	return nil
+}
+
+// CVExperience is a job parsed from the experience section.
+type CVExperience struct {
+	ID          string     `gorm:"type:uuid;primaryKey" json:"id"`
+	CVID        string     `gorm:"column:cv_id;type:uuid;not null;index" json:"cv_id"`
+	Position    int        `gorm:"not null" json:"position"`
+	Role        string     `json:"role"`
+	Employer    string     `json:"employer"`
+	StartDate   *time.Time `gorm:"type:date" json:"start_date"`
+	EndDate     *time.Time `gorm:"type:date" json:"end_date"`
+	IsCurrent   bool       `gorm:"not null;default:false" json:"is_current"`
+	Description string     `gorm:"type:text" json:"description"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CVExperience) TableName() string {
+	return "cv_experiences"
+}
+
+func (e *CVExperience) BeforeCreate(tx *gorm.DB) (err error) {
+	e.ID = uuid.NewString()
+	e.CreatedAt = time.Now()
+	return nil
+}
+
+// CVEducation is a degree or school parsed from the education section.
+type CVEducation struct {
+	ID          string     `gorm:"type:uuid;primaryKey" json:"id"`
+	CVID        string     `gorm:"column:cv_id;type:uuid;not null;index" json:"cv_id"`
+	Position    int        `gorm:"not null" json:"position"`
+	Institution string     `json:"institution"`
+	Degree      string     `json:"degree"`
+	StartDate   *time.Time `gorm:"type:date" json:"start_date"`
+	EndDate     *time.Time `gorm:"type:date" json:"end_date"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CVEducation) TableName() string {
+	return "cv_educations"
+}
+
+func (e *CVEducation) BeforeCreate(tx *gorm.DB) (err error) {
+	e.ID = uuid.NewString()
+	e.CreatedAt = time.Now()
+	return nil
+}
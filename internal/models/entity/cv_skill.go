@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CVSkill is a canonical skill mentioned in a CV.
+type CVSkill struct {
+	ID       string `gorm:"type:uuid;primaryKey" json:"id"`
+	CVID     string `gorm:"column:cv_id;type:uuid;not null;index" json:"cv_id"`
+	Name     string `gorm:"not null" json:"name"`
+	Category string `json:"category"`
+	Mentions int    `gorm:"not null;default:1" json:"mentions"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CVSkill) TableName() string {
+	return "cv_skills"
+}
+
+func (s *CVSkill) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.NewString()
+	s.CreatedAt = time.Now()
+	return nil
+}
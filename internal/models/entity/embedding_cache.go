@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/pgvector/pgvector-go"
+)
+
+// EmbeddingCacheEntry is a cached embedding, keyed by the SHA-256 of model,
+// dimension and normalized text.
+type EmbeddingCacheEntry struct {
+	Hash      string          `gorm:"type:char(64);primaryKey" json:"hash"`
+	Model     string          `gorm:"not null" json:"model"`
+	Dim       int             `gorm:"not null" json:"dim"`
+	Embedding pgvector.Vector `gorm:"type:vector;not null" json:"embedding"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (EmbeddingCacheEntry) TableName() string {
+	return "embedding_cache"
+}
@@ -12,6 +12,10 @@ type EvaluationRubrics struct {
 	Project []Rubric `json:"project"`
 }
 
+// RubricExperienceLevel is cross-checked against the years computed from
+// employment dates.
+const RubricExperienceLevel = "Experience Level"
+
 // NewDefaultRubrics returns the standard “Rubrics Cube” set.
 func NewDefaultRubrics() EvaluationRubrics {
 	return EvaluationRubrics{
@@ -23,7 +27,7 @@ func NewDefaultRubrics() EvaluationRubrics {
 				Scale:       "1=Irrelevant, 2=Few overlaps, 3=Partial, 4=Strong, 5=Excellent+AI/LLM",
 			},
 			{
-				Name:        "Experience Level",
+				Name:        RubricExperienceLevel,
 				Weight:      0.25,
 				Description: "Years of experience and project complexity.",
 				Scale:       "1=<1yr, 2=1–2yrs, 3=2–3yrs, 4=3–4yrs, 5=5+yrs",
@@ -75,3 +79,19 @@ func NewDefaultRubrics() EvaluationRubrics {
 		},
 	}
 }
+
+// WeightedScore is the weighted mean of scores over rubrics, on the 1-5
+// scale; criteria without a score are left out. ok is false when none has one.
+func WeightedScore(rubrics []Rubric, scores ScoreMap) (score float64, ok bool) {
+	var sum, weights float64
+	for _, r := range rubrics {
+		if v, found := scores[r.Name]; found {
+			sum += r.Weight * v
+			weights += r.Weight
+		}
+	}
+	if weights == 0 {
+		return 0, false
+	}
+	return sum / weights, true
+}
@@ -0,0 +1,205 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/GazDuckington/go-gin/pkgs/cvparse"
+	"github.com/GazDuckington/go-gin/pkgs/scoring"
+	"github.com/GazDuckington/go-gin/pkgs/skills"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ScoreMap holds per-criterion scores, stored as JSONB.
+type ScoreMap map[string]float64
+
+func (m ScoreMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+func (m *ScoreMap) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	default:
+		return fmt.Errorf("cannot scan %T into ScoreMap", value)
+	}
+}
+
+// Review states of an evaluation.
+const (
+	ReviewPending    = "pending_review"
+	ReviewApproved   = "approved"
+	ReviewOverridden = "overridden"
+)
+
+// Evaluation is one stored LLM evaluation of a CV, tagged with the model and
+// prompt version that produced it.
+type Evaluation struct {
+	ID              string   `gorm:"type:uuid;primaryKey" json:"id"`
+	CVID            string   `gorm:"column:cv_id;type:uuid;not null;index" json:"cv_id"`
+	Model           string   `gorm:"not null" json:"model"`
+	PromptVersion   string   `gorm:"not null" json:"prompt_version"`
+	Blind           bool     `gorm:"not null;default:false" json:"blind"`
+	CVScores        ScoreMap `gorm:"type:jsonb" json:"cv_scores"`
+	ProjectScores   ScoreMap `gorm:"type:jsonb" json:"project_scores"`
+	CVMatchRate     float64  `json:"cv_match_rate"`
+	CVFeedback      string   `gorm:"type:text" json:"cv_feedback"`
+	ProjectScore    float64  `json:"project_score"`
+	ProjectFeedback string   `gorm:"type:text" json:"project_feedback"`
+	OverallSummary  string   `gorm:"type:text" json:"overall_summary"`
+
+	ExperienceCheck *cvparse.ExperienceCheck `gorm:"type:jsonb;serializer:json" json:"experience_check,omitempty"`
+	SkillMatch      *skills.MatchResult      `gorm:"type:jsonb;serializer:json" json:"skill_match,omitempty"`
+	// Hybrid blends the LLM match rate with the rule-based scorers.
+	Hybrid *scoring.Result `gorm:"type:jsonb;serializer:json" json:"hybrid,omitempty"`
+
+	ReviewStatus          string     `gorm:"not null;default:pending_review" json:"review_status"`
+	ReviewedBy            *string    `gorm:"type:uuid" json:"reviewed_by"`
+	ReviewedAt            *time.Time `json:"reviewed_at"`
+	OverrideCVScores      ScoreMap   `gorm:"type:jsonb" json:"override_cv_scores"`
+	OverrideProjectScores ScoreMap   `gorm:"type:jsonb" json:"override_project_scores"`
+	OverrideSummary       *string    `gorm:"type:text" json:"override_summary"`
+	OverrideReason        *string    `gorm:"type:text" json:"override_reason"`
+
+	CV          *CV          `gorm:"foreignKey:CVID;constraint:OnDelete:CASCADE" json:"cv,omitempty"`
+	HumanScores []HumanScore `gorm:"foreignKey:EvaluationID;constraint:OnDelete:CASCADE" json:"human_scores,omitempty"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+func (Evaluation) TableName() string {
+	return "evaluations"
+}
+
+func (e *Evaluation) BeforeCreate(tx *gorm.DB) (err error) {
+	e.ID = uuid.NewString()
+	e.CreatedAt = time.Now()
+	return nil
+}
+
+func (e *Evaluation) BeforeUpdate(tx *gorm.DB) (err error) {
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// LLMScore returns the model's score for a rubric criterion, CV or project.
+func (e *Evaluation) LLMScore(criterion string) (float64, bool) {
+	if v, ok := e.CVScores[criterion]; ok {
+		return v, true
+	}
+	v, ok := e.ProjectScores[criterion]
+	return v, ok
+}
+
+// EffectiveCVScores are the LLM scores with any reviewer overrides applied.
+func (e *Evaluation) EffectiveCVScores() ScoreMap {
+	return mergeScores(e.CVScores, e.OverrideCVScores)
+}
+
+// EffectiveProjectScores are the LLM scores with any reviewer overrides applied.
+func (e *Evaluation) EffectiveProjectScores() ScoreMap {
+	return mergeScores(e.ProjectScores, e.OverrideProjectScores)
+}
+
+// EffectiveCVMatchRate is the CV match rate on 0-1, recomputed from the
+// effective criterion scores when a reviewer overrode any of them.
+func (e *Evaluation) EffectiveCVMatchRate() float64 {
+	if len(e.OverrideCVScores) > 0 {
+		if v, ok := WeightedScore(NewDefaultRubrics().CV, e.EffectiveCVScores()); ok {
+			return v / 5
+		}
+	}
+	return e.CVMatchRate
+}
+
+// EffectiveProjectScore is the project score on 1-5, recomputed from the
+// effective criterion scores when a reviewer overrode any of them.
+func (e *Evaluation) EffectiveProjectScore() float64 {
+	if len(e.OverrideProjectScores) > 0 {
+		if v, ok := WeightedScore(NewDefaultRubrics().Project, e.EffectiveProjectScores()); ok {
+			return v
+		}
+	}
+	return e.ProjectScore
+}
+
+// EffectiveSummary is the reviewer's summary when overridden, else the LLM's.
+func (e *Evaluation) EffectiveSummary() string {
+	if e.OverrideSummary != nil {
+		return *e.OverrideSummary
+	}
+	return e.OverallSummary
+}
+
+func mergeScores(base, override ScoreMap) ScoreMap {
+	out := ScoreMap{}
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		out[k] = v
+	}
+	return out
+}
+
+// EvaluationAudit records one reviewer action; overrides write one row per
+// changed field.
+type EvaluationAudit struct {
+	ID           string `gorm:"type:uuid;primaryKey" json:"id"`
+	EvaluationID string `gorm:"type:uuid;not null;index" json:"evaluation_id"`
+	ActorID      string `gorm:"type:uuid;not null" json:"actor_id"`
+	Action       string `gorm:"not null" json:"action"`
+	Field        string `json:"field,omitempty"`
+	OldValue     string `gorm:"type:text" json:"old_value,omitempty"`
+	NewValue     string `gorm:"type:text" json:"new_value,omitempty"`
+	Reason       string `gorm:"type:text" json:"reason,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (EvaluationAudit) TableName() string {
+	return "evaluation_audits"
+}
+
+func (a *EvaluationAudit) BeforeCreate(tx *gorm.DB) (err error) {
+	a.ID = uuid.NewString()
+	a.CreatedAt = time.Now()
+	return nil
+}
+
+// HumanScore is a recruiter's own score for one rubric criterion.
+type HumanScore struct {
+	ID           string  `gorm:"type:uuid;primaryKey" json:"id"`
+	EvaluationID string  `gorm:"type:uuid;not null" json:"evaluation_id"`
+	ReviewerID   string  `gorm:"type:uuid;not null" json:"reviewer_id"`
+	Criterion    string  `gorm:"not null" json:"criterion"`
+	Score        float64 `gorm:"not null" json:"score"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (HumanScore) TableName() string {
+	return "human_scores"
+}
+
+func (h *HumanScore) BeforeCreate(tx *gorm.DB) (err error) {
+	h.ID = uuid.NewString()
+	h.CreatedAt = time.Now()
+	h.UpdatedAt = h.CreatedAt
+	return nil
+}
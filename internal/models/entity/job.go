@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Job struct {
+	ID          string `gorm:"type:uuid;primaryKey" json:"id"`
+	Title       string `gorm:"size:150;not null" json:"title"`
+	Description string `gorm:"type:text" json:"description"`
+	Blind       bool   `gorm:"not null;default:false" json:"blind"`
+	CreatedBy   string `gorm:"type:uuid" json:"created_by"`
+
+	// RequiredSkills are canonical skill names, see pkgs/skills.
+	RequiredSkills []string `gorm:"type:jsonb;serializer:json;not null" json:"required_skills"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+}
+
+func (Job) TableName() string {
+	return "jobs"
+}
+
+func (j *Job) BeforeCreate(tx *gorm.DB) (err error) {
+	j.ID = uuid.NewString()
+	j.CreatedAt = time.Now()
+	return nil
+}
+
+func (j *Job) BeforeUpdate(tx *gorm.DB) (err error) {
+	j.UpdatedAt = time.Now()
+	return nil
+}
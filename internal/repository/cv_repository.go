@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	database "github.com/GazDuckington/go-gin/db"
 	"github.com/GazDuckington/go-gin/internal/config"
@@ -13,6 +14,34 @@ import (
 type CVRepository interface {
 	Submit(ctx context.Context, newCv *entity.CV) (*entity.CV, error)
 	GetCv(ctx context.Context, id string) (*entity.CV, error)
+	KeywordSearch(ctx context.Context, q KeywordQuery) ([]CVKeywordHit, error)
+	FindByJob(ctx context.Context, jobID string) ([]entity.CV, error)
+	ListAfter(ctx context.Context, after CVCursor, limit int) ([]entity.CV, error)
+	FindWithSkills(ctx context.Context, jobID string, limit int) ([]entity.CV, error)
+}
+
+// CVCursor is a position in all CVs ordered by creation time and ID; the
+// zero cursor is the start.
+type CVCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+}
+
+// KeywordQuery is a full-text search over cvs.search_vector. Query uses
+// websearch_to_tsquery syntax; UserID, when set, limits it to one owner.
+type KeywordQuery struct {
+	Query  string
+	UserID string
+	Limit  int
+}
+
+// CVKeywordHit is a CV matched by keyword search with its ts_rank_cd rank.
+type CVKeywordHit struct {
+	ID          string
+	UserID      string
+	Title       string
+	ContentType string
+	Rank        float64
 }
 
 type cvRepository struct {
@@ -41,7 +70,15 @@ func (r *cvRepository) GetCv(ctx context.Context, id string) (*entity.CV, error)
 	var cv entity.CV
 
 	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
-		return tx.WithContext(ctx).First(&cv, "id = ?", id).Error
+		return tx.WithContext(ctx).
+			Preload("Job").
+			Preload("User.Profile").
+			Preload("Contacts").
+			Preload("Sections", byPosition).
+			Preload("Experiences", byPosition).
+			Preload("Educations", byPosition).
+			Preload("Skills", func(db *gorm.DB) *gorm.DB { return db.Order("mentions DESC, name") }).
+			First(&cv, "id = ?", id).Error
 	})
 	if err != nil {
 		return nil, err
@@ -49,3 +86,83 @@ func (r *cvRepository) GetCv(ctx context.Context, id string) (*entity.CV, error)
 
 	return &cv, nil
 }
+
+func byPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+func (r *cvRepository) KeywordSearch(ctx context.Context, q KeywordQuery) ([]CVKeywordHit, error) {
+	var hits []CVKeywordHit
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		db := tx.WithContext(ctx).
+			Table("cvs, websearch_to_tsquery('simple', ?) AS query", q.Query).
+			Select("cvs.id, cvs.user_id, cvs.title, cvs.content_type, ts_rank_cd(cvs.search_vector, query) AS rank").
+			Where("cvs.search_vector @@ query AND cvs.deleted_at IS NULL")
+		if q.UserID != "" {
+			db = db.Where("cvs.user_id = ?", q.UserID)
+		}
+		return db.Order("rank DESC, cvs.created_at DESC").Limit(q.Limit).Scan(&hits).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// FindByJob lists the CVs submitted to a job with their owner's profile,
+// without the CV text.
+func (r *cvRepository) FindByJob(ctx context.Context, jobID string) ([]entity.CV, error) {
+	var cvs []entity.CV
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.WithContext(ctx).
+			Select("id", "user_id", "job_id", "title", "content_type", "needs_ocr", "created_at").
+			Preload("User.Profile").
+			Where("job_id = ?", jobID).
+			Order("created_at").
+			Find(&cvs).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cvs, nil
+}
+
+// ListAfter pages through all CVs in creation order with the fields needed
+// to embed them, without their parsed sections.
+func (r *cvRepository) ListAfter(ctx context.Context, after CVCursor, limit int) ([]entity.CV, error) {
+	var cvs []entity.CV
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		db := tx.WithContext(ctx).
+			Select("id", "user_id", "job_id", "title", "content_type", "summary", "created_at")
+		if after.ID != "" {
+			db = db.Where("(created_at, id) > (?, ?)", after.CreatedAt, after.ID)
+		}
+		return db.Order("created_at, id").Limit(limit).Find(&cvs).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cvs, nil
+}
+
+// FindWithSkills lists the latest CVs, of one job when jobID is set, with
+// their skills and the roles of their experiences, without the CV text.
+func (r *cvRepository) FindWithSkills(ctx context.Context, jobID string, limit int) ([]entity.CV, error) {
+	var cvs []entity.CV
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		db := tx.WithContext(ctx).
+			Select("id", "user_id", "job_id", "title", "created_at").
+			Preload("Skills").
+			Preload("Experiences", func(db *gorm.DB) *gorm.DB {
+				return db.Select("id", "cv_id", "position", "role").Order("position")
+			})
+		if jobID != "" {
+			db = db.Where("job_id = ?", jobID)
+		}
+		return db.Order("created_at DESC, id").Limit(limit).Find(&cvs).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cvs, nil
+}
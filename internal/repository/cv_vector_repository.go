@@ -0,0 +1,405 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	database "github.com/GazDuckington/go-gin/db"
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+	"github.com/pgvector/pgvector-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CVVectorStore is a vectorstore.VectorStore over the cvs.embedding pgvector
+// column and the cv_chunks table, so a deployment can run without a vector
+// database. The payload comes from the CV row itself, which must exist
+// before its vector is stored.
+type CVVectorStore struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+	dim    int
+	// iterativeScan is set by Init when pgvector can keep scanning the
+	// HNSW index until enough rows pass the filter (0.8 and later).
+	iterativeScan bool
+}
+
+var _ vectorstore.VectorStore = (*CVVectorStore)(nil)
+
+func NewCVVectorStore(db *gorm.DB, cfg *config.Config) *CVVectorStore {
+	return &CVVectorStore{
+		db:     db,
+		logger: cfg.Logger,
+		dim:    cfg.EmbeddingDim,
+	}
+}
+
+// Init fails when cvs.embedding or cv_chunks.embedding holds vectors of
+// another size than EMBEDDING_DIM, see Resize, or is missing because the
+// migrations have not run. It also checks whether filtered searches can use
+// iterative index scans.
+func (s *CVVectorStore) Init(ctx context.Context) error {
+	var version string
+	err := s.db.WithContext(ctx).Raw(`SELECT extversion FROM pg_extension WHERE extname = 'vector'`).Scan(&version).Error
+	if err != nil {
+		return err
+	}
+	if version == "" {
+		return errors.New("the pgvector extension is not installed, run the migrations")
+	}
+	s.iterativeScan = versionAtLeast(version, 0, 8)
+	if !s.iterativeScan {
+		s.logger.Warnf("pgvector %s has no iterative index scans, filtered searches scan every vector; upgrade to 0.8 or later", version)
+	}
+
+	for _, table := range []string{"cvs", "cv_chunks"} {
+		var dim int
+		err := s.db.WithContext(ctx).Raw(`
+			SELECT a.atttypmod FROM pg_attribute a
+			JOIN pg_type t ON t.oid = a.atttypid
+			WHERE a.attrelid = to_regclass(?) AND a.attname = 'embedding' AND t.typname = 'vector'`, table).
+			Scan(&dim).Error
+		if err != nil {
+			return err
+		}
+		if dim <= 0 {
+			return fmt.Errorf("%s.embedding is not a pgvector column, run the migrations", table)
+		}
+		if dim != s.dim {
+			return fmt.Errorf("%w: %s.embedding has %d dimensions, EMBEDDING_DIM is %d",
+				vectorstore.ErrDimension, table, dim, s.dim)
+		}
+	}
+	return nil
+}
+
+// versionAtLeast compares a "major.minor[.patch]" version.
+func versionAtLeast(version string, major, minor int) bool {
+	var gotMajor, gotMinor int
+	if _, err := fmt.Sscanf(version, "%d.%d", &gotMajor, &gotMinor); err != nil {
+		return false
+	}
+	return gotMajor > major || gotMajor == major && gotMinor >= minor
+}
+
+// Resize changes cvs.embedding and cv_chunks.embedding to EMBEDDING_DIM
+// values. Vectors of another size cannot be converted, so every stored vector
+// and chunk is dropped: search finds nothing until the CVs are embedded
+// again, see cmd/reindex.
+func (s *CVVectorStore) Resize(ctx context.Context) error {
+	return database.RunInTransaction(ctx, s.db, s.logger, func(tx *gorm.DB) error {
+		for _, stmt := range []string{
+			`DROP INDEX IF EXISTS idx_cvs_embedding`,
+			`DROP INDEX IF EXISTS idx_cv_chunks_embedding`,
+			`DELETE FROM cv_chunks`,
+			fmt.Sprintf(`ALTER TABLE cvs ALTER COLUMN embedding TYPE vector(%d) USING NULL`, s.dim),
+			fmt.Sprintf(`ALTER TABLE cv_chunks ALTER COLUMN embedding TYPE vector(%d)`, s.dim),
+			`CREATE INDEX idx_cvs_embedding ON cvs USING hnsw (embedding vector_cosine_ops)`,
+			`CREATE INDEX idx_cv_chunks_embedding ON cv_chunks USING hnsw (embedding vector_cosine_ops)`,
+		} {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *CVVectorStore) Name() string { return "pgvector" }
+
+// Upsert replaces the chunks of the points along with their vectors.
+func (s *CVVectorStore) Upsert(ctx context.Context, points ...vectorstore.Point) error {
+	for _, p := range points {
+		if err := vectorstore.CheckPoint(p, s.dim); err != nil {
+			return err
+		}
+	}
+	return database.RunInTransaction(ctx, s.db, s.logger, func(tx *gorm.DB) error {
+		for _, p := range points {
+			res := tx.Table("cvs").
+				Where("id = ? AND deleted_at IS NULL", p.ID).
+				UpdateColumn("embedding", pgvector.NewVector(p.Vector))
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return fmt.Errorf("%w: no CV %s to store the vector of", vectorstore.ErrNotFound, p.ID)
+			}
+
+			if err := tx.Where("cv_id = ?", p.ID).Delete(&entity.CVChunk{}).Error; err != nil {
+				return err
+			}
+			if len(p.Chunks) == 0 {
+				continue
+			}
+			chunks := make([]entity.CVChunk, len(p.Chunks))
+			for i, c := range p.Chunks {
+				chunks[i] = entity.CVChunk{
+					CVID:      p.ID,
+					Position:  i,
+					Section:   c.Section,
+					Embedding: pgvector.NewVector(c.Vector),
+				}
+			}
+			if err := tx.Create(&chunks).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// cvVectorRow is a CV's payload with its vector or its search score.
+type cvVectorRow struct {
+	ID          string
+	UserID      string
+	JobID       *string
+	Title       string
+	ContentType string
+	CreatedAt   time.Time
+	Embedding   *pgvector.Vector
+	Score       float64
+}
+
+func (r cvVectorRow) point() vectorstore.Point {
+	p := vectorstore.Point{
+		ID:          r.ID,
+		UserID:      r.UserID,
+		Title:       r.Title,
+		ContentType: r.ContentType,
+		CreatedAt:   r.CreatedAt,
+	}
+	if r.JobID != nil {
+		p.JobID = *r.JobID
+	}
+	if r.Embedding != nil {
+		p.Vector = r.Embedding.Slice()
+	}
+	return p
+}
+
+const cvPayloadColumns = "id, user_id, job_id, title, content_type, created_at"
+
+func (s *CVVectorStore) Get(ctx context.Context, ids ...string) ([]vectorstore.Point, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var rows []cvVectorRow
+	err := database.RunInTransaction(ctx, s.db, s.logger, func(tx *gorm.DB) error {
+		return tx.Table("cvs").
+			Select(cvPayloadColumns+", embedding").
+			Where("id IN ? AND embedding IS NOT NULL AND deleted_at IS NULL", ids).
+			Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]vectorstore.Point, len(rows))
+	for i, r := range rows {
+		out[i] = r.point()
+	}
+	return out, nil
+}
+
+func (s *CVVectorStore) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return database.RunInTransaction(ctx, s.db, s.logger, func(tx *gorm.DB) error {
+		if err := tx.Where("cv_id IN ?", ids).Delete(&entity.CVChunk{}).Error; err != nil {
+			return err
+		}
+		return tx.Table("cvs").Where("id IN ?", ids).UpdateColumn("embedding", nil).Error
+	})
+}
+
+// Search orders by cosine distance so the HNSW index is used. The index is
+// filtered after the scan, see prepareScan.
+func (s *CVVectorStore) Search(ctx context.Context, q vectorstore.Query) ([]vectorstore.Hit, error) {
+	if q.Aggregate.Mode != "" && q.Like == "" {
+		return s.searchChunks(ctx, q)
+	}
+
+	var rows []cvVectorRow
+	err := database.RunInTransaction(ctx, s.db, s.logger, func(tx *gorm.DB) error {
+		vector := q.Vector
+		if q.Like != "" {
+			var like cvVectorRow
+			err := tx.Table("cvs").
+				Select("embedding").
+				Where("id = ? AND embedding IS NOT NULL AND deleted_at IS NULL", q.Like).
+				Take(&like).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return vectorstore.ErrNotFound
+			}
+			if err != nil {
+				return err
+			}
+			vector = like.Embedding.Slice()
+		}
+		if err := vectorstore.CheckDim(vector, s.dim); err != nil {
+			return err
+		}
+
+		if err := s.prepareScan(tx, q.Limit+q.Offset, q.Filter, "strict_order"); err != nil {
+			return err
+		}
+
+		v := pgvector.NewVector(vector)
+		db := tx.Table("cvs").
+			Select(cvPayloadColumns+", 1 - (embedding <=> ?) AS score", v).
+			Where("embedding IS NOT NULL AND deleted_at IS NULL")
+		db = cvVectorFilter(db, q.Filter)
+		if q.Threshold > 0 {
+			db = db.Where("1 - (embedding <=> ?) >= ?", v, q.Threshold)
+		}
+		db = db.Order(clause.Expr{SQL: "embedding <=> ?", Vars: []any{v}}).Offset(q.Offset)
+		if q.Limit > 0 {
+			db = db.Limit(q.Limit)
+		}
+		return db.Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]vectorstore.Hit, len(rows))
+	for i, r := range rows {
+		hits[i] = vectorstore.Hit{Point: r.point(), Score: r.Score}
+	}
+	return hits, nil
+}
+
+// chunkCandidates is how many chunks per CV asked for are ranked, so CVs
+// still have enough of theirs among the nearest chunks.
+const chunkCandidates = 4
+
+// searchChunks ranks the CVs by q.Aggregate over the nearest chunks of the
+// CVs passing the filter, then loads their payload. CVs without chunks are
+// compared by their CV embedding as if it were their only chunk.
+func (s *CVVectorStore) searchChunks(ctx context.Context, q vectorstore.Query) ([]vectorstore.Hit, error) {
+	if err := vectorstore.CheckDim(q.Vector, s.dim); err != nil {
+		return nil, err
+	}
+
+	var ranked []vectorstore.ChunkScore
+	byID := map[string]cvVectorRow{}
+	err := database.RunInTransaction(ctx, s.db, s.logger, func(tx *gorm.DB) error {
+		limit := 0
+		if q.Limit > 0 {
+			limit = (q.Offset + q.Limit) * max(q.Aggregate.TopK, 1) * chunkCandidates
+		}
+		// the chunks are ranked again below, so their order may be relaxed
+		if err := s.prepareScan(tx, limit, q.Filter, "relaxed_order"); err != nil {
+			return err
+		}
+
+		v := pgvector.NewVector(q.Vector)
+		cvs := cvVectorFilter(tx.Table("cvs").Select("id").Where("deleted_at IS NULL"), q.Filter)
+		db := tx.Table("cv_chunks").
+			Select("cv_id AS id, 1 - (embedding <=> ?) AS score", v).
+			Where("cv_id IN (?)", cvs).
+			Order(clause.Expr{SQL: "embedding <=> ?", Vars: []any{v}})
+		if limit > 0 {
+			db = db.Limit(limit)
+		}
+		var scores []vectorstore.ChunkScore
+		if err := db.Scan(&scores).Error; err != nil {
+			return err
+		}
+
+		unchunked := cvVectorFilter(tx.Table("cvs").
+			Select("id, 1 - (embedding <=> ?) AS score", v).
+			Where("deleted_at IS NULL AND embedding IS NOT NULL").
+			Where("NOT EXISTS (SELECT 1 FROM cv_chunks WHERE cv_chunks.cv_id = cvs.id)"), q.Filter).
+			Order(clause.Expr{SQL: "embedding <=> ?", Vars: []any{v}})
+		if q.Limit > 0 {
+			unchunked = unchunked.Limit(q.Offset + q.Limit)
+		}
+		var cvScores []vectorstore.ChunkScore
+		if err := unchunked.Scan(&cvScores).Error; err != nil {
+			return err
+		}
+		scores = append(scores, cvScores...)
+
+		ranked = vectorstore.Page(q.Aggregate.Rank(scores), q)
+		if len(ranked) == 0 {
+			return nil
+		}
+		ids := make([]string, len(ranked))
+		for i, r := range ranked {
+			ids[i] = r.ID
+		}
+		var rows []cvVectorRow
+		if err := tx.Table("cvs").Select(cvPayloadColumns).Where("id IN ?", ids).Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, r := range rows {
+			byID[r.ID] = r
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]vectorstore.Hit, 0, len(ranked))
+	for _, r := range ranked {
+		if row, ok := byID[r.ID]; ok {
+			hits = append(hits, vectorstore.Hit{Point: row.point(), Score: r.Score})
+		}
+	}
+	return hits, nil
+}
+
+// prepareScan makes sure an ordered search finds rows enough. The HNSW
+// index yields ef_search candidates before the filter is applied, so
+// ef_search is raised to the number of rows asked for. A filter can still
+// drop most candidates, e.g. a user's own CVs among thousands: pgvector 0.8
+// then keeps scanning the index in the given order (strict_order or
+// relaxed_order), older versions skip the index for an exact scan when the
+// filter narrows the rows down.
+func (s *CVVectorStore) prepareScan(tx *gorm.DB, rows int, f vectorstore.Filter, order string) error {
+	if rows > 40 {
+		if err := tx.Exec(fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", min(rows, 1000))).Error; err != nil {
+			return err
+		}
+	}
+
+	narrowing := f.UserID != "" || f.JobID != "" || !f.Since.IsZero() || !f.Until.IsZero()
+	switch {
+	case s.iterativeScan && (narrowing || f.ExcludeUserID != "" || len(f.ExcludeIDs) > 0):
+		return tx.Exec("SET LOCAL hnsw.iterative_scan = " + order).Error
+	case narrowing:
+		return tx.Exec("SET LOCAL enable_indexscan = off").Error
+	}
+	return nil
+}
+
+func cvVectorFilter(db *gorm.DB, f vectorstore.Filter) *gorm.DB {
+	if f.UserID != "" {
+		db = db.Where("user_id = ?", f.UserID)
+	}
+	if f.ExcludeUserID != "" {
+		db = db.Where("user_id <> ?", f.ExcludeUserID)
+	}
+	if f.JobID != "" {
+		db = db.Where("job_id = ?", f.JobID)
+	}
+	if len(f.ExcludeIDs) > 0 {
+		db = db.Where("id NOT IN ?", f.ExcludeIDs)
+	}
+	if !f.Since.IsZero() {
+		db = db.Where("created_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		db = db.Where("created_at < ?", f.Until)
+	}
+	return db
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	database "github.com/GazDuckington/go-gin/db"
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/GazDuckington/go-gin/pkgs/embedcache"
+	"github.com/pgvector/pgvector-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type embeddingCacheRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+// NewEmbeddingCacheRepository keeps cached embeddings in the embedding_cache
+// table.
+func NewEmbeddingCacheRepository(db *gorm.DB, cfg *config.Config) embedcache.Store {
+	return &embeddingCacheRepository{
+		db:     db,
+		logger: cfg.Logger,
+	}
+}
+
+func (r *embeddingCacheRepository) Get(ctx context.Context, keys []string) (map[string][]float32, error) {
+	var entries []entity.EmbeddingCacheEntry
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.Where("hash IN ?", keys).Find(&entries).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]float32, len(entries))
+	for _, e := range entries {
+		out[e.Hash] = e.Embedding.Slice()
+	}
+	return out, nil
+}
+
+func (r *embeddingCacheRepository) Put(ctx context.Context, model string, dim int, embeddings map[string][]float32) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+	entries := make([]entity.EmbeddingCacheEntry, 0, len(embeddings))
+	for hash, v := range embeddings {
+		entries = append(entries, entity.EmbeddingCacheEntry{
+			Hash:      hash,
+			Model:     model,
+			Dim:       dim,
+			Embedding: pgvector.NewVector(v),
+		})
+	}
+	return database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&entries, 100).Error
+	})
+}
+
+func (r *embeddingCacheRepository) Purge(ctx context.Context, model string, dim int) (int64, error) {
+	var deleted int64
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		db := tx.Where("1 = 1")
+		if model != "" {
+			db = tx.Where("model <> ? OR dim <> ?", model, dim)
+		}
+		res := db.Delete(&entity.EmbeddingCacheEntry{})
+		deleted = res.RowsAffected
+		return res.Error
+	})
+	return deleted, err
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	database "github.com/GazDuckington/go-gin/db"
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStaleReview is returned by SaveReview when the evaluation's review state
+// changed after it was read.
+var ErrStaleReview = errors.New("evaluation changed since it was read")
+
+type EvaluationRepository interface {
+	Create(ctx context.Context, eval *entity.Evaluation) error
+	FindByID(ctx context.Context, id string) (*entity.Evaluation, error)
+	SaveHumanScores(ctx context.Context, scores []entity.HumanScore) error
+	FindWithHumanScores(ctx context.Context) ([]entity.Evaluation, error)
+	FindByReviewStatus(ctx context.Context, status string) ([]entity.Evaluation, error)
+	SaveReview(ctx context.Context, eval *entity.Evaluation, readStatus string, readAt time.Time, audits []entity.EvaluationAudit) error
+	FindAudits(ctx context.Context, evalID string) ([]entity.EvaluationAudit, error)
+	LatestForCVs(ctx context.Context, cvIDs []string) ([]entity.Evaluation, error)
+}
+
+type evaluationRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+func NewEvaluationRepository(db *gorm.DB, cfg *config.Config) EvaluationRepository {
+	return &evaluationRepository{
+		db:     db,
+		logger: cfg.Logger,
+	}
+}
+
+func (r *evaluationRepository) Create(ctx context.Context, eval *entity.Evaluation) error {
+	return database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.Create(eval).Error
+	})
+}
+
+func (r *evaluationRepository) FindByID(ctx context.Context, id string) (*entity.Evaluation, error) {
+	var eval entity.Evaluation
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.Preload("HumanScores").First(&eval, "id = ?", id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &eval, nil
+}
+
+// SaveHumanScores inserts scores, replacing a reviewer's earlier score for the
+// same criterion.
+func (r *evaluationRepository) SaveHumanScores(ctx context.Context, scores []entity.HumanScore) error {
+	return database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "evaluation_id"}, {Name: "reviewer_id"}, {Name: "criterion"}},
+			DoUpdates: clause.AssignmentColumns([]string{"score", "updated_at"}),
+		}).Create(&scores).Error
+	})
+}
+
+// FindWithHumanScores returns every evaluation that at least one recruiter has
+// scored, with those scores preloaded.
+func (r *evaluationRepository) FindWithHumanScores(ctx context.Context) ([]entity.Evaluation, error) {
+	var evals []entity.Evaluation
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.Preload("HumanScores").
+			Where("EXISTS (SELECT 1 FROM human_scores hs WHERE hs.evaluation_id = evaluations.id)").
+			Find(&evals).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return evals, nil
+}
+
+// FindByReviewStatus lists evaluations in a review state, oldest first so the
+// review queue is worked in order. An empty status lists all.
+func (r *evaluationRepository) FindByReviewStatus(ctx context.Context, status string) ([]entity.Evaluation, error) {
+	var evals []entity.Evaluation
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		q := tx.Order("created_at ASC")
+		if status != "" {
+			q = q.Where("review_status = ?", status)
+		}
+		return q.Find(&evals).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return evals, nil
+}
+
+// SaveReview stores the review columns of eval and its audit rows atomically,
+// so there is never a review without a trail. The update only applies while
+// the row still has the review_status and updated_at it was read with;
+// otherwise another reviewer got there first and ErrStaleReview is returned.
+func (r *evaluationRepository) SaveReview(ctx context.Context, eval *entity.Evaluation, readStatus string, readAt time.Time, audits []entity.EvaluationAudit) error {
+	return database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		res := tx.Model(eval).
+			Where("review_status = ? AND updated_at = ?", readStatus, readAt).
+			Select(
+				"review_status", "reviewed_by", "reviewed_at", "updated_at",
+				"override_cv_scores", "override_project_scores", "override_summary", "override_reason",
+			).Updates(eval)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrStaleReview
+		}
+		if len(audits) == 0 {
+			return nil
+		}
+		return tx.Create(&audits).Error
+	})
+}
+
+func (r *evaluationRepository) FindAudits(ctx context.Context, evalID string) ([]entity.EvaluationAudit, error) {
+	var audits []entity.EvaluationAudit
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.Where("evaluation_id = ?", evalID).Order("created_at ASC").Find(&audits).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
+// LatestForCVs returns the newest evaluation of each CV that has one.
+func (r *evaluationRepository) LatestForCVs(ctx context.Context, cvIDs []string) ([]entity.Evaluation, error) {
+	var evals []entity.Evaluation
+	if len(cvIDs) == 0 {
+		return evals, nil
+	}
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.Raw(`SELECT DISTINCT ON (cv_id) * FROM evaluations
+			WHERE cv_id IN ? AND deleted_at IS NULL
+			ORDER BY cv_id, created_at DESC`, cvIDs).
+			Scan(&evals).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return evals, nil
+}
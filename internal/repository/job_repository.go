@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	database "github.com/GazDuckington/go-gin/db"
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type JobRepository interface {
+	Create(ctx context.Context, job *entity.Job) error
+	FindAll(ctx context.Context) ([]entity.Job, error)
+	FindByID(ctx context.Context, id string) (*entity.Job, error)
+}
+
+type jobRepository struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+func NewJobRepository(db *gorm.DB, cfg *config.Config) JobRepository {
+	return &jobRepository{
+		db:     db,
+		logger: cfg.Logger,
+	}
+}
+
+func (r *jobRepository) Create(ctx context.Context, job *entity.Job) error {
+	return database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.Create(job).Error
+	})
+}
+
+func (r *jobRepository) FindAll(ctx context.Context) ([]entity.Job, error) {
+	var jobs []entity.Job
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.Order("created_at DESC").Find(&jobs).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) FindByID(ctx context.Context, id string) (*entity.Job, error) {
+	var job entity.Job
+	err := database.RunInTransaction(ctx, r.db, r.logger, func(tx *gorm.DB) error {
+		return tx.First(&job, "id = ?", id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	database "github.com/GazDuckington/go-gin/db"
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/controller"
+	"github.com/GazDuckington/go-gin/internal/middleware"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/GazDuckington/go-gin/pkgs/embedcache"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAdminRoutes(r *gin.Engine, cfg *config.Config, store vectorstore.VectorStore, cache *embedcache.Cache) {
+	jobRepo := repository.NewJobRepository(database.DB, cfg)
+	cvRepo := repository.NewCVRepository(database.DB, cfg)
+	evalRepo := repository.NewEvaluationRepository(database.DB, cfg)
+	analyticsSvc := service.NewAnalyticsService(jobRepo, cvRepo, evalRepo, store, cfg)
+	adminCtrl := controller.NewAdminController(analyticsSvc, cache, cfg)
+
+	g := r.Group("/admin")
+	g.Use(
+		middleware.AuthRequired([]byte(cfg.JWTSecret), cfg.Logger),
+		middleware.RoleRequired("admin"),
+	)
+	{
+		g.GET("/analytics/clusters", adminCtrl.Clusters)
+		g.GET("/embedding-cache", adminCtrl.EmbeddingCacheStats)
+		g.DELETE("/embedding-cache", adminCtrl.InvalidateEmbeddingCache)
+	}
+}
@@ -7,13 +7,15 @@ import (
 	"github.com/GazDuckington/go-gin/internal/middleware"
 	"github.com/GazDuckington/go-gin/internal/repository"
 	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterCvRoutes(r *gin.Engine, cfg *config.Config) {
+func RegisterCvRoutes(r *gin.Engine, cfg *config.Config, store vectorstore.VectorStore) {
 	cvRepo := repository.NewCVRepository(database.DB, cfg)
-	cvSvc := service.NewCVService(cvRepo, cfg)
-	cvWrk := service.NewCVWorkerService(cfg, cvRepo)
+	cvSvc := service.NewCVService(cvRepo, store, cfg)
+	evalRepo := repository.NewEvaluationRepository(database.DB, cfg)
+	cvWrk := service.NewCVWorkerService(cfg, cvRepo, evalRepo, store)
 	cvCtrl := controller.NewCvController(cvSvc, cfg, cvWrk)
 
 	g := r.Group("/cv")
@@ -21,6 +23,8 @@ func RegisterCvRoutes(r *gin.Engine, cfg *config.Config) {
 	{
 		g.POST("", cvCtrl.SubmitCv)
 		g.GET("/:id", cvCtrl.GetCv)
+		g.GET("/:id/anonymized", cvCtrl.GetAnonymized)
+		g.GET("/:id/similar", middleware.RoleRequired("admin"), cvCtrl.GetSimilar)
 		g.POST("/:id", cvCtrl.EvaluateCv)
 		g.GET("status/:id", cvCtrl.GetEvalStatus)
 		g.GET("result/:id", cvCtrl.EvaluationResult)
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	database "github.com/GazDuckington/go-gin/db"
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/controller"
+	"github.com/GazDuckington/go-gin/internal/middleware"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterEvaluationRoutes(r *gin.Engine, cfg *config.Config) {
+	evalRepo := repository.NewEvaluationRepository(database.DB, cfg)
+	evalSvc := service.NewEvaluationService(evalRepo, cfg)
+	evalCtrl := controller.NewEvaluationController(evalSvc, cfg)
+
+	g := r.Group("/evaluations")
+	g.Use(
+		middleware.AuthRequired([]byte(cfg.JWTSecret), cfg.Logger),
+		middleware.RoleRequired("admin"),
+	)
+	{
+		g.GET("", evalCtrl.List)
+		g.GET("/:id", evalCtrl.GetByID)
+		g.GET("/:id/audit", evalCtrl.Audit)
+		g.POST("/:id/approve", evalCtrl.Approve)
+		g.POST("/:id/override", evalCtrl.Override)
+		g.POST("/:id/human-scores", evalCtrl.SubmitHumanScores)
+	}
+
+	admin := r.Group("/admin")
+	admin.Use(
+		middleware.AuthRequired([]byte(cfg.JWTSecret), cfg.Logger),
+		middleware.RoleRequired("admin"),
+	)
+	{
+		admin.GET("/calibration", evalCtrl.Calibration)
+	}
+}
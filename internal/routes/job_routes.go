@@ -0,0 +1,35 @@
+package routes
+
+import (
+	database "github.com/GazDuckington/go-gin/db"
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/controller"
+	"github.com/GazDuckington/go-gin/internal/middleware"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterJobRoutes(r *gin.Engine, cfg *config.Config, store vectorstore.VectorStore) {
+	jobRepo := repository.NewJobRepository(database.DB, cfg)
+	jobSvc := service.NewJobService(jobRepo)
+	jobCtrl := controller.NewJobController(jobSvc, cfg)
+	rankingSvc := service.NewRankingService(
+		jobRepo,
+		repository.NewCVRepository(database.DB, cfg),
+		repository.NewEvaluationRepository(database.DB, cfg),
+		store,
+		cfg,
+	)
+	rankingCtrl := controller.NewRankingController(rankingSvc, cfg)
+
+	g := r.Group("/jobs")
+	g.Use(middleware.AuthRequired([]byte(cfg.JWTSecret), cfg.Logger))
+	{
+		g.GET("", jobCtrl.GetAll)
+		g.GET("/:id", jobCtrl.GetByID)
+		g.POST("", middleware.RoleRequired("admin"), jobCtrl.Create)
+		g.GET("/:id/ranking", middleware.RoleRequired("admin"), rankingCtrl.RankJob)
+	}
+}
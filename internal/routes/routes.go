@@ -4,10 +4,12 @@ import (
 	"github.com/GazDuckington/go-gin/internal/config"
 	"github.com/GazDuckington/go-gin/internal/controller"
 	"github.com/GazDuckington/go-gin/internal/middleware"
+	"github.com/GazDuckington/go-gin/pkgs/embedcache"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(cfg *config.Config) *gin.Engine {
+func SetupRouter(cfg *config.Config, store vectorstore.VectorStore, cache *embedcache.Cache) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Recovery())
 	r.Use(
@@ -22,6 +24,10 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	// NOTE: register domains
 	RegisterUserRoutes(r, cfg)
 	RegisterAuthRoutes(r, cfg)
-	RegisterCvRoutes(r, cfg)
+	RegisterCvRoutes(r, cfg, store)
+	RegisterJobRoutes(r, cfg, store)
+	RegisterEvaluationRoutes(r, cfg)
+	RegisterSearchRoutes(r, cfg, store)
+	RegisterAdminRoutes(r, cfg, store, cache)
 	return r
 }
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	database "github.com/GazDuckington/go-gin/db"
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/controller"
+	"github.com/GazDuckington/go-gin/internal/middleware"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	"github.com/GazDuckington/go-gin/internal/service"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSearchRoutes(r *gin.Engine, cfg *config.Config, store vectorstore.VectorStore) {
+	cvRepo := repository.NewCVRepository(database.DB, cfg)
+	jobRepo := repository.NewJobRepository(database.DB, cfg)
+	searchSvc := service.NewSearchService(cvRepo, jobRepo, store, cfg)
+	searchCtrl := controller.NewSearchController(searchSvc, cfg)
+
+	g := r.Group("/search")
+	g.Use(middleware.AuthRequired([]byte(cfg.JWTSecret), cfg.Logger))
+	{
+		g.POST("/cvs", searchCtrl.SearchCVs)
+	}
+}
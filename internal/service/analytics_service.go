@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	"github.com/GazDuckington/go-gin/pkgs/stats"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxClusterCVs is how many of the latest CVs are clustered at most.
+	maxClusterCVs = 5000
+	// vectorBatch is how many vectors are read from the store at once.
+	vectorBatch         = 500
+	kmeansMaxIterations = 50
+	// defaultClusterTop is how many skills and titles label a cluster.
+	defaultClusterTop = 5
+	// clusterRepresentatives are the members closest to the centroid shown.
+	clusterRepresentatives = 3
+)
+
+type AnalyticsService interface {
+	// ClusterCVs clusters the CVs of a job, or all CVs without a job ID.
+	// It returns nil for an unknown job.
+	ClusterCVs(ctx context.Context, q dto.ClusterQuery) (*dto.ClusterAnalyticsResponse, error)
+}
+
+type analyticsService struct {
+	jobRepo  repository.JobRepository
+	cvRepo   repository.CVRepository
+	evalRepo repository.EvaluationRepository
+	store    vectorstore.VectorStore
+	cfg      *config.Config
+}
+
+func NewAnalyticsService(jobRepo repository.JobRepository, cvRepo repository.CVRepository, evalRepo repository.EvaluationRepository, store vectorstore.VectorStore, cfg *config.Config) AnalyticsService {
+	return &analyticsService{jobRepo: jobRepo, cvRepo: cvRepo, evalRepo: evalRepo, store: store, cfg: cfg}
+}
+
+func (s *analyticsService) ClusterCVs(ctx context.Context, q dto.ClusterQuery) (*dto.ClusterAnalyticsResponse, error) {
+	resp := &dto.ClusterAnalyticsResponse{Clusters: []dto.CVCluster{}}
+	if q.JobID != "" {
+		job, err := s.jobRepo.FindByID(ctx, q.JobID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.JobID, resp.JobTitle = job.ID, job.Title
+	}
+
+	cvs, err := s.cvRepo.FindWithSkills(ctx, q.JobID, maxClusterCVs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*entity.CV, len(cvs))
+	ids := make([]string, len(cvs))
+	for i := range cvs {
+		byID[cvs[i].ID] = &cvs[i]
+		ids[i] = cvs[i].ID
+	}
+
+	var points []vectorstore.Point
+	for start := 0; start < len(ids); start += vectorBatch {
+		batch, err := s.store.Get(ctx, ids[start:min(start+vectorBatch, len(ids))]...)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, batch...)
+	}
+	// the same CVs in the same order cluster the same way
+	sort.Slice(points, func(i, j int) bool { return points[i].ID < points[j].ID })
+	resp.CVs, resp.WithoutVector = len(points), len(cvs)-len(points)
+	if len(points) == 0 {
+		return resp, nil
+	}
+
+	k := q.K
+	if k == 0 {
+		k = defaultClusterCount(len(points))
+	}
+	vectors := make([][]float32, len(points))
+	for i, p := range points {
+		vectors[i] = p.Vector
+	}
+	result := stats.KMeans(vectors, k, kmeansMaxIterations, 1)
+	resp.K = len(result.Centroids)
+
+	evals, err := s.evalRepo.LatestForCVs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]*entity.Evaluation, len(evals))
+	for i := range evals {
+		latest[evals[i].CVID] = &evals[i]
+	}
+
+	top := q.Top
+	if top == 0 {
+		top = defaultClusterTop
+	}
+	members := make([][]int, resp.K)
+	for i, c := range result.Assign {
+		members[c] = append(members[c], i)
+	}
+	for c, idx := range members {
+		if len(idx) == 0 {
+			continue
+		}
+		cluster := dto.CVCluster{
+			Size:  len(idx),
+			Share: float64(len(idx)) / float64(len(points)),
+		}
+
+		skillCounts, titleCounts := map[string]int{}, map[string]int{}
+		var evalSum, projectSum, simSum float64
+		var projects int
+		reps := make([]dto.ClusterMember, 0, len(idx))
+		for _, i := range idx {
+			cv := byID[points[i].ID]
+			for _, term := range cvSkillNames(cv) {
+				skillCounts[term]++
+			}
+			for _, term := range cvRoles(cv) {
+				titleCounts[term]++
+			}
+			if e, ok := latest[cv.ID]; ok {
+				cvScore, project, hasProject := evaluationScores(e)
+				cluster.Evaluated++
+				evalSum += cvScore
+				if hasProject {
+					projects++
+					projectSum += project
+				}
+			}
+			sim := stats.Cosine(points[i].Vector, result.Centroids[c])
+			if math.IsNaN(sim) {
+				sim = 0
+			}
+			simSum += sim
+			reps = append(reps, dto.ClusterMember{CVID: cv.ID, Title: cv.Title, Similarity: sim})
+		}
+
+		cluster.Cohesion = simSum / float64(len(idx))
+		cluster.TopSkills = topTerms(skillCounts, top)
+		cluster.TopTitles = topTerms(titleCounts, top)
+		if cluster.Evaluated > 0 {
+			avg := evalSum / float64(cluster.Evaluated)
+			cluster.AvgEvaluation = &avg
+		}
+		if projects > 0 {
+			avg := projectSum / float64(projects)
+			cluster.AvgProject = &avg
+		}
+		sort.Slice(reps, func(i, j int) bool {
+			if reps[i].Similarity != reps[j].Similarity {
+				return reps[i].Similarity > reps[j].Similarity
+			}
+			return reps[i].CVID < reps[j].CVID
+		})
+		cluster.Representatives = reps[:min(clusterRepresentatives, len(reps))]
+		cluster.Label = clusterLabel(cluster)
+		resp.Clusters = append(resp.Clusters, cluster)
+	}
+
+	sort.SliceStable(resp.Clusters, func(i, j int) bool { return resp.Clusters[i].Size > resp.Clusters[j].Size })
+	for i := range resp.Clusters {
+		resp.Clusters[i].ID = i + 1
+	}
+	return resp, nil
+}
+
+// defaultClusterCount is the rule of thumb k = sqrt(n/2), kept between 2
+// and 8 so the landscape stays readable.
+func defaultClusterCount(n int) int {
+	return max(2, min(8, int(math.Round(math.Sqrt(float64(n)/2)))))
+}
+
+// cvSkillNames are the distinct canonical skills of a CV.
+func cvSkillNames(cv *entity.CV) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range cv.Skills {
+		if name := strings.ToLower(strings.TrimSpace(s.Name)); name != "" && !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// cvRoles are the distinct job titles of a CV's experiences.
+func cvRoles(cv *entity.CV) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, e := range cv.Experiences {
+		role := strings.ToLower(strings.Join(strings.Fields(e.Role), " "))
+		if role != "" && !seen[role] {
+			seen[role] = true
+			out = append(out, role)
+		}
+	}
+	return out
+}
+
+// topTerms are the n most frequent terms, ties in alphabetical order.
+func topTerms(counts map[string]int, n int) []dto.TermCount {
+	out := make([]dto.TermCount, 0, len(counts))
+	for term, count := range counts {
+		out = append(out, dto.TermCount{Term: term, Count: count})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Term < out[j].Term
+	})
+	return out[:min(n, len(out))]
+}
+
+// clusterLabel names a cluster after its three most frequent skills, or its
+// most frequent title when its CVs list no skills.
+func clusterLabel(c dto.CVCluster) string {
+	var terms []string
+	for _, t := range c.TopSkills[:min(3, len(c.TopSkills))] {
+		terms = append(terms, t.Term)
+	}
+	if len(terms) == 0 && len(c.TopTitles) > 0 {
+		terms = append(terms, c.TopTitles[0].Term)
+	}
+	if len(terms) == 0 {
+		return "unlabelled"
+	}
+	return strings.Join(terms, ", ")
+}
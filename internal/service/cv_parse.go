@@ -0,0 +1,102 @@
+package service
+
+import (
+	"time"
+
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/GazDuckington/go-gin/pkgs/cvparse"
+	"github.com/GazDuckington/go-gin/pkgs/skills"
+)
+
+// attachParsed parses the CV text into sections, jobs, degrees and skills,
+// to be saved together with the CV.
+func attachParsed(cv *entity.CV, text string) {
+	parsed := cvparse.Parse(text, time.Now())
+
+	for i, s := range parsed.Sections {
+		cv.Sections = append(cv.Sections, entity.CVSection{
+			Position: i,
+			Kind:     string(s.Kind),
+			Heading:  s.Heading,
+			Content:  s.Content,
+		})
+	}
+	for i, e := range parsed.Experiences {
+		cv.Experiences = append(cv.Experiences, entity.CVExperience{
+			Position:    i,
+			Role:        e.Role,
+			Employer:    e.Employer,
+			StartDate:   datePtr(e.Dates.Start),
+			EndDate:     datePtr(e.Dates.End),
+			IsCurrent:   e.Dates.Current,
+			Description: e.Description,
+		})
+	}
+	for i, e := range parsed.Educations {
+		cv.Educations = append(cv.Educations, entity.CVEducation{
+			Position:    i,
+			Institution: e.Institution,
+			Degree:      e.Degree,
+			StartDate:   datePtr(e.Dates.Start),
+			EndDate:     datePtr(e.Dates.End),
+		})
+	}
+	for _, s := range skills.Default().Find(text) {
+		cv.Skills = append(cv.Skills, entity.CVSkill{
+			Name:     s.Name,
+			Category: s.Category,
+			Mentions: s.Mentions,
+		})
+	}
+}
+
+// fillParsed copies the stored parse results into the API response.
+func fillParsed(res *dto.CVResponse, cv *entity.CV) {
+	for _, s := range cv.Sections {
+		res.Sections = append(res.Sections, dto.SectionResponse{
+			Kind:    s.Kind,
+			Heading: s.Heading,
+			Content: s.Content,
+		})
+	}
+	for _, e := range cv.Experiences {
+		res.Experiences = append(res.Experiences, dto.ExperienceResponse{
+			Role:        e.Role,
+			Employer:    e.Employer,
+			StartDate:   monthString(e.StartDate),
+			EndDate:     monthString(e.EndDate),
+			Current:     e.IsCurrent,
+			Description: e.Description,
+		})
+	}
+	for _, e := range cv.Educations {
+		res.Educations = append(res.Educations, dto.EducationResponse{
+			Institution: e.Institution,
+			Degree:      e.Degree,
+			StartDate:   monthString(e.StartDate),
+			EndDate:     monthString(e.EndDate),
+		})
+	}
+	for _, s := range cv.Skills {
+		res.Skills = append(res.Skills, skills.Skill{
+			Name:     s.Name,
+			Category: s.Category,
+			Mentions: s.Mentions,
+		})
+	}
+}
+
+func datePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func monthString(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01")
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/GazDuckington/go-gin/pkgs/contacts"
+	"github.com/GazDuckington/go-gin/pkgs/cvparse"
+	gemini "github.com/GazDuckington/go-gin/pkgs/genai"
+	"github.com/GazDuckington/go-gin/pkgs/redact"
+	"github.com/GazDuckington/go-gin/pkgs/scoring"
+	"github.com/GazDuckington/go-gin/pkgs/skills"
+)
+
+// EvaluationPipeline turns CV text into an evaluation. It is shared by the
+// background worker and cmd/evalbench so both exercise the same steps.
+type EvaluationPipeline struct {
+	cfg       *config.Config
+	redactor  *redact.Redactor
+	generator gemini.Generator
+	scorers   []Scorer
+	weights   scoring.Weights
+}
+
+// EvaluationInput is everything the pipeline needs about one CV.
+type EvaluationInput struct {
+	CV    *dto.CVResponse
+	Blind bool
+	// Names are known candidate names, stripped in blind mode.
+	Names []string
+	// AsOf is the date ongoing jobs are counted up to; zero means now.
+	AsOf time.Time
+	// Job is the posting the CV applies to, nil when none.
+	Job *dto.JobResponse
+}
+
+// NewEvaluationPipeline uses the built-in scorers with embed for the
+// similarity scorer; embed may be nil.
+func NewEvaluationPipeline(cfg *config.Config, gen gemini.Generator, embed EmbedFunc) *EvaluationPipeline {
+	weights, err := scoring.ParseWeights(cfg.ScoreWeights)
+	if err != nil {
+		cfg.Logger.Warnf("[pipeline] invalid SCORE_WEIGHTS, using %s: %v", DefaultScoreWeights, err)
+		weights, _ = scoring.ParseWeights(DefaultScoreWeights)
+	}
+	return &EvaluationPipeline{
+		cfg:       cfg,
+		redactor:  newRedactor(cfg),
+		generator: gen,
+		scorers:   DefaultScorers(embed),
+		weights:   weights,
+	}
+}
+
+// DefaultScoreWeights is used when SCORE_WEIGHTS cannot be parsed.
+const DefaultScoreWeights = "llm=0.6,skills=0.2,similarity=0.1,experience=0.1"
+
+// AddScorer adds a scorer to the hybrid score; it counts once SCORE_WEIGHTS
+// gives its name a weight.
+func (p *EvaluationPipeline) AddScorer(s Scorer) {
+	p.scorers = append(p.scorers, s)
+}
+
+// Evaluate sends a redacted copy of the CV to the LLM and puts the original
+// contact values back into the feedback it returns. In blind mode identity
+// attributes are stripped first and are never restored.
+func (p *EvaluationPipeline) Evaluate(ctx context.Context, in EvaluationInput) (*dto.CVEvaluationResponse, error) {
+	cv := in.CV
+	outbound := *cv
+	outbound.Embedding = nil
+	summary := cv.Summary
+	if in.Blind {
+		summary, _ = redact.Anonymize(summary, in.Names...)
+		// titles like "Jane Doe - CV" name the candidate, and names we do not
+		// know cannot be stripped from a single line
+		outbound.Title = ""
+	}
+	summary, mapping := p.redactor.Redact(summary)
+	outbound.Summary = summary
+	if p.redactor.Enabled() || in.Blind {
+		// presigned URLs carry the uploaded file name, which often is the candidate's name
+		outbound.FilePath = ""
+	}
+
+	outbound.Contacts = p.profileLinks(cv.Contacts, in.Blind)
+
+	// the LLM guesses years of experience badly, so hand it the computed ones
+	asOf := in.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	// relevance is judged against the job applied for, or else the CV's own title
+	target, label := cv.Title, outbound.Title
+	if in.Job != nil && in.Job.Title != "" {
+		target, label = in.Job.Title, in.Job.Title
+	}
+	years := cvparse.ComputeExperience(cvparse.Parse(cv.Summary, asOf).Experiences, target, asOf)
+	if years.TotalMonths > 0 {
+		outbound.Facts = append(outbound.Facts, experienceFacts(years, label)...)
+	}
+
+	var skillMatch *skills.MatchResult
+	if in.Job != nil && len(in.Job.RequiredSkills) > 0 {
+		taxonomy := skills.Default()
+		found := cv.Skills
+		if len(found) == 0 {
+			found = taxonomy.Find(cv.Summary)
+		}
+		m := taxonomy.Match(in.Job.RequiredSkills, found, cv.Summary)
+		skillMatch = &m
+		outbound.Facts = append(outbound.Facts, skillFacts(m)...)
+	}
+
+	eval, err := gemini.EvaluateCVWith(ctx, p.generator, &outbound)
+	if err != nil {
+		return nil, err
+	}
+	p.cfg.Logger.Debugf("[pipeline] evaluated CV: %s | Title: %s | redacted: %d | blind: %t", cv.ID, cv.Title, len(mapping), in.Blind)
+
+	eval.CVFeedback = mapping.Restore(eval.CVFeedback)
+	eval.ProjectFeedback = mapping.Restore(eval.ProjectFeedback)
+	eval.OverallSummary = mapping.Restore(eval.OverallSummary)
+	eval.BlindMode = in.Blind
+	eval.SkillMatch = skillMatch
+
+	if llm, ok := eval.CVScores[entity.RubricExperienceLevel]; ok && years.TotalMonths > 0 {
+		check := cvparse.CheckExperienceScore(years, llm)
+		eval.ExperienceCheck = &check
+		if !check.Consistent {
+			p.cfg.Logger.Warnf("[pipeline] CV %s: LLM scored %s %.0f, %.1f relevant / %.1f total years map to %d",
+				cv.ID, entity.RubricExperienceLevel, llm, check.RelevantYears, check.TotalYears, check.ExpectedScore)
+		}
+	}
+
+	hybrid := p.score(ctx, ScoreInput{
+		CV:         cv,
+		Job:        in.Job,
+		Eval:       eval,
+		Experience: years,
+		SkillMatch: skillMatch,
+	})
+	eval.Hybrid = &hybrid
+	return eval, nil
+}
+
+// score runs every scorer and blends the results. A failing scorer is left
+// out of the blend like one that does not apply.
+func (p *EvaluationPipeline) score(ctx context.Context, in ScoreInput) scoring.Result {
+	scores := map[string]float64{}
+	for _, s := range p.scorers {
+		v, ok, err := s.Score(ctx, in)
+		if err != nil {
+			p.cfg.Logger.Warnf("[pipeline] scorer %s failed for CV %s: %v", s.Name(), in.CV.ID, err)
+			continue
+		}
+		if ok {
+			scores[s.Name()] = v
+		}
+	}
+	return scoring.Blend(p.weights, scores)
+}
+
+// profileLinks are the contacts the LLM may see: profile and portfolio links
+// that survive the redaction policy. Emails and phone numbers say nothing
+// about the candidate's fit, and in blind mode links identify them.
+func (p *EvaluationPipeline) profileLinks(all []dto.ContactResponse, blind bool) []dto.ContactResponse {
+	if blind {
+		return nil
+	}
+	var links []dto.ContactResponse
+	for _, c := range all {
+		if !(contacts.Contact{Kind: contacts.Kind(c.Kind)}).IsLink() {
+			continue
+		}
+		if redacted, _ := p.redactor.Redact(c.Value); redacted != c.Value {
+			continue
+		}
+		links = append(links, c)
+	}
+	return links
+}
+
+func experienceFacts(y cvparse.ExperienceYears, title string) []string {
+	facts := []string{fmt.Sprintf("Total professional experience: %.1f years (from employment dates, overlapping jobs counted once)", y.TotalYears)}
+	if title != "" {
+		facts = append(facts, fmt.Sprintf("Experience in roles relevant to %q: %.1f years", title, y.RelevantYears))
+	}
+	return facts
+}
+
+func skillFacts(m skills.MatchResult) []string {
+	facts := []string{fmt.Sprintf("Required skills found in the CV: %d of %d (%s)",
+		len(m.Matched), len(m.Required), strings.Join(m.Matched, ", "))}
+	if len(m.Missing) > 0 {
+		facts = append(facts, "Required skills not mentioned in the CV: "+strings.Join(m.Missing, ", "))
+	}
+	return facts
+}
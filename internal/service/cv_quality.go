@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/GazDuckington/go-gin/pkgs/utils"
+)
+
+// Low quality actions, see config.LowQualityAction.
+const (
+	LowQualityFlag   = "flag"
+	LowQualityReject = "reject"
+)
+
+func (s *cvService) qualityThresholds() utils.QualityThresholds {
+	return utils.QualityThresholds{
+		MinCharsPerPage:    s.cfg.QualityMinCharsPerPage,
+		MinPrintableRatio:  s.cfg.QualityMinPrintableRatio,
+		MinDictionaryRatio: s.cfg.QualityMinDictionaryRatio,
+		MaxEmptyPageRatio:  s.cfg.QualityMaxEmptyPageRatio,
+	}
+}
+
+// assessText scores extracted text and, when it looks like a scanned
+// document, tries the OCR hook. It returns the text to keep, its quality and
+// whether the CV still needs OCR; with the reject action that is an error.
+func (s *cvService) assessText(ctx context.Context, path, contentType, text string) (string, utils.Quality, bool, error) {
+	thresholds := s.qualityThresholds()
+	quality := utils.MeasureQuality(text)
+	problems := quality.Problems(thresholds)
+	if len(problems) == 0 {
+		return text, quality, false, nil
+	}
+
+	if s.cfg.OCRCommand != "" && contentType == utils.MIMEPDF {
+		ocrCtx, cancel := context.WithTimeout(ctx, s.cfg.OCRTimeout)
+		ocrText, err := utils.RunOCR(ocrCtx, s.cfg.OCRCommand, path)
+		cancel()
+		if err != nil {
+			s.cfg.Logger.Warnf("ocr failed: %v", err)
+		} else {
+			ocrQuality := utils.MeasureQuality(ocrText)
+			ocrQuality.OCR = true
+			ocrProblems := ocrQuality.Problems(thresholds)
+			if len(ocrProblems) == 0 {
+				return ocrText, ocrQuality, false, nil
+			}
+			problems = ocrProblems
+		}
+	}
+
+	if s.cfg.LowQualityAction == LowQualityReject {
+		return "", quality, true, &utils.UploadError{
+			Code:    utils.CodeNeedsOCR,
+			Status:  http.StatusUnprocessableEntity,
+			Message: "the file looks scanned or has no readable text: " + strings.Join(problems, "; "),
+		}
+	}
+	s.cfg.Logger.Infof("cv flagged as needs_ocr: %s", strings.Join(problems, "; "))
+	return text, quality, true, nil
+}
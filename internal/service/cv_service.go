@@ -2,46 +2,73 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/GazDuckington/go-gin/internal/config"
 	"github.com/GazDuckington/go-gin/internal/models/dto"
 	"github.com/GazDuckington/go-gin/internal/models/entity"
 	"github.com/GazDuckington/go-gin/internal/repository"
+	"github.com/GazDuckington/go-gin/pkgs/contacts"
+	"github.com/GazDuckington/go-gin/pkgs/cvparse"
 	gemini "github.com/GazDuckington/go-gin/pkgs/genai"
 	"github.com/GazDuckington/go-gin/pkgs/minio"
-	"github.com/GazDuckington/go-gin/pkgs/qdrant"
+	"github.com/GazDuckington/go-gin/pkgs/redact"
+	"github.com/GazDuckington/go-gin/pkgs/stats"
 	"github.com/GazDuckington/go-gin/pkgs/utils"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCVForbidden is returned when the caller neither owns the CV nor is an admin.
+	ErrCVForbidden = errors.New("not allowed to read this CV")
+	// ErrCVNoEmbedding is returned when a CV has no vector to search with yet.
+	ErrCVNoEmbedding = errors.New("CV has no embedding")
 )
 
 type CVService interface {
 	SubmitCV(ctx context.Context, req dto.SubmitCvRequest) (*entity.CV, error)
-	GetCv(ctx context.Context, id string) (*dto.CVResponse, error)
+	GetCv(ctx context.Context, who Searcher, id string) (*dto.CVResponse, error)
+	GetAnonymized(ctx context.Context, who Searcher, id string) (*dto.AnonymizedCVResponse, error)
+	GetSimilar(ctx context.Context, id string, q dto.SimilarCVsQuery) (*dto.SimilarCVsResponse, error)
 }
 
 type cvService struct {
 	repo        repository.CVRepository
+	store       vectorstore.VectorStore
 	minioBucket string
 	cfg         *config.Config
+	redactor    *redact.Redactor
 }
 
-func NewCVService(r repository.CVRepository, cfg *config.Config) CVService {
+func NewCVService(r repository.CVRepository, store vectorstore.VectorStore, cfg *config.Config) CVService {
 	return &cvService{
 		repo:        r,
+		store:       store,
 		minioBucket: cfg.MinioBucket,
 		cfg:         cfg,
+		redactor:    newRedactor(cfg),
 	}
 }
 
 func (s *cvService) SubmitCV(ctx context.Context, req dto.SubmitCvRequest) (*entity.CV, error) {
 	// Ensure MinIO bucket exists
 	if err := minio.EnsureBucket(ctx, s.minioBucket); err != nil {
-		return nil, errors.New(fmt.Sprintf("failed to ensure bucket: %w", err))
+		return nil, fmt.Errorf("failed to ensure bucket: %w", err)
+	}
+
+	limits := utils.UploadLimits{
+		MaxBytes: int64(s.cfg.MaxUploadBytes),
+		MaxPages: s.cfg.MaxPDFPages,
+	}
+	if err := utils.CheckSize(req.File.Size, limits); err != nil {
+		return nil, err
 	}
 
 	// Create unique file name
@@ -54,74 +81,298 @@ func (s *cvService) SubmitCV(ctx context.Context, req dto.SubmitCvRequest) (*ent
 	}
 	defer uploadedFile.Close()
 
-	// Create a temp file
-	tmpFile, err := os.CreateTemp("", "cv-*.pdf")
+	// Create a temp file, keeping the original extension for the sniffers
+	tmpFile, err := os.CreateTemp("", "cv-*"+filepath.Ext(req.File.Filename))
 	if err != nil {
 		return nil, fmt.Errorf("cannot create temp file: %w", err)
 	}
 	defer os.Remove(tmpFile.Name()) // clean up after upload
+	defer tmpFile.Close()
 
 	// Copy uploaded content to temp file
 	if _, err := io.Copy(tmpFile, uploadedFile); err != nil {
 		return nil, fmt.Errorf("cannot copy uploaded file: %w", err)
 	}
 
-	// extract all texts from pdf
-	text, err := utils.ExtractTextFromPDF(tmpFile.Name())
+	// detect the real type from the content and reject anything we can't read,
+	// then extract with the matching extractor
+	contentType, err := utils.ValidateUpload(tmpFile.Name(), req.File.Filename, limits)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract text: %v", err)
+		return nil, err
 	}
-	// generate embedding from pdf
-	embeds, err := gemini.GenerateEmbedding(ctx, text)
+	text, err := utils.ExtractText(tmpFile.Name(), contentType)
+	if err != nil && !errors.Is(err, utils.ErrNoText) {
+		s.cfg.Logger.Warnf("text extraction failed for %s: %v", req.File.Filename, err)
+		return nil, &utils.UploadError{
+			Code:    utils.CodeCorruptedFile,
+			Status:  http.StatusUnprocessableEntity,
+			Message: "no text could be extracted from the file",
+		}
+	}
+
+	// scanned, image-only CVs extract to little or garbled text
+	text, quality, needsOCR, err := s.assessText(ctx, tmpFile.Name(), contentType, text)
 	if err != nil {
 		return nil, err
 	}
 
-	uploaded, err := minio.UploadPDF(ctx, s.minioBucket, objName, tmpFile.Name())
+	// profile links usually live in link annotations rather than in the text
+	links, err := utils.ExtractLinks(tmpFile.Name(), contentType)
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("failed to upload file: %w", err))
+		s.cfg.Logger.Warnf("link extraction failed for %s: %v", req.File.Filename, err)
 	}
+	found := contacts.Extract(text, links)
 
-	// Create entity
-	newCv := &entity.CV{
-		UserID:   req.UserID,
-		Title:    req.Title,
-		Summary:  text,
-		FilePath: uploaded.Key,
+	vector, chunks, err := embedCV(ctx, s.redactor, gemini.GenerateEmbeddings, s.cfg.ChunkMaxChars, text)
+	if err != nil {
+		return nil, err
 	}
 
-	// Save to DB
-	embeddingJSON, err := json.Marshal(embeds)
+	uploaded, err := minio.UploadFile(ctx, s.minioBucket, objName, tmpFile.Name(), contentType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal embedding: %w", err)
+		return nil, fmt.Errorf("failed to upload file: %w", err)
 	}
-	mb, err := utils.BytesToFloat32sBinary(embeddingJSON)
-	if err != nil {
-		s.cfg.Logger.Warnf("error storing embeds: %v", err)
+
+	// Create entity
+	newCv := &entity.CV{
+		UserID:      req.UserID,
+		Title:       req.Title,
+		Summary:     text,
+		FilePath:    uploaded.Key,
+		ContentType: contentType,
+
+		NeedsOCR:          needsOCR,
+		ExtractionQuality: &quality,
 	}
-	newCv.Embedding = mb
+	attachParsed(newCv, text)
+	for _, c := range found {
+		newCv.Contacts = append(newCv.Contacts, entity.CVContact{
+			Kind:   string(c.Kind),
+			Value:  c.Value,
+			Source: c.Source,
+		})
+	}
+	if req.JobID != "" {
+		newCv.JobID = &req.JobID
+	}
+
+	// Save to DB; the vector store keeps the embedding
 	created, err := s.repo.Submit(ctx, newCv)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save CV: %w", err)
 	}
 
-	created.Embedding = embeds
-	if err := qdrant.StoreToQdrant(created, s.cfg); err != nil {
-		return nil, fmt.Errorf("qdrant upsert failed: %v", err)
+	if err := s.store.Upsert(ctx, cvPoint(created, vector, chunks)); err != nil {
+		return nil, fmt.Errorf("%s upsert failed: %w", s.store.Name(), err)
 	}
 
 	return created, nil
 }
 
-func (s *cvService) GetCv(ctx context.Context, id string) (*dto.CVResponse, error) {
+// GetCv returns a CV with its contacts and parsed sections. Only admins and
+// the owner may read it; nil when the CV does not exist.
+func (s *cvService) GetCv(ctx context.Context, who Searcher, id string) (*dto.CVResponse, error) {
+	cv, err := s.repo.GetCv(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !who.Admin && cv.UserID != who.UserID {
+		return nil, ErrCVForbidden
+	}
+	res := cvResponse(ctx, s.cfg, s.store, cv)
+	res.NeedsOCR = cv.NeedsOCR
+	res.ExtractionQuality = cv.ExtractionQuality
+	res.Contacts = contactResponses(cv)
+	fillParsed(res, cv)
+	return res, nil
+}
+
+// cvResponse maps a stored CV with a presigned download URL and its vector
+// from the store. Without either the CV is still usable, so failures are
+// only logged.
+func cvResponse(ctx context.Context, cfg *config.Config, store vectorstore.VectorStore, cv *entity.CV) *dto.CVResponse {
+	res := &dto.CVResponse{
+		ID:          cv.ID,
+		UserID:      cv.UserID,
+		Title:       cv.Title,
+		Summary:     cv.Summary,
+		FilePath:    cv.FilePath,
+		ContentType: cv.ContentType,
+	}
+
+	if url, err := minio.GetPresignedURL(ctx, cfg.MinioBucket, cv.FilePath, time.Hour); err != nil {
+		// fall back to the object key if presign fails
+		cfg.Logger.Warnf("failed to presign %s: %v", cv.FilePath, err)
+	} else {
+		res.FilePath = url
+	}
+
+	points, err := store.Get(ctx, cv.ID)
+	switch {
+	case err != nil:
+		cfg.Logger.Warnf("failed to get vector of CV %s from %s: %v", cv.ID, store.Name(), err)
+	case len(points) == 0:
+		cfg.Logger.Warnf("CV %s has no vector in %s", cv.ID, store.Name())
+	default:
+		res.Embedding = points[0].Vector
+	}
+	return res
+}
+
+// embedCV embeds the CV text in chunks of at most maxChars, with PII masked
+// before it leaves the server, in one batch. The CV vector is the mean of
+// the chunk vectors; text without sections is embedded whole.
+func embedCV(ctx context.Context, redactor *redact.Redactor, embed EmbedBatchFunc, maxChars int, text string) ([]float32, []vectorstore.Chunk, error) {
+	redacted, _ := redactor.Redact(text)
+	pieces := cvparse.Chunks(redacted, maxChars)
+	if len(pieces) == 0 {
+		vectors, err := embed(ctx, []string{redacted})
+		if err != nil {
+			return nil, nil, err
+		}
+		return vectors[0], nil, nil
+	}
+
+	texts := make([]string, len(pieces))
+	for i, c := range pieces {
+		texts[i] = c.Text
+	}
+	vectors, err := embed(ctx, texts)
+	if err != nil {
+		return nil, nil, err
+	}
+	chunks := make([]vectorstore.Chunk, len(pieces))
+	for i, c := range pieces {
+		chunks[i] = vectorstore.Chunk{Section: string(c.Section), Vector: vectors[i]}
+	}
+	return stats.MeanVector(vectors), chunks, nil
+}
+
+// cvPoint is what the vector store keeps of a CV.
+func cvPoint(cv *entity.CV, vector []float32, chunks []vectorstore.Chunk) vectorstore.Point {
+	p := vectorstore.Point{
+		ID:          cv.ID,
+		Vector:      vector,
+		Chunks:      chunks,
+		UserID:      cv.UserID,
+		Title:       cv.Title,
+		ContentType: cv.ContentType,
+		CreatedAt:   cv.CreatedAt,
+	}
+	if cv.JobID != nil {
+		p.JobID = *cv.JobID
+	}
+	return p
+}
+
+// GetAnonymized returns the CV text as a blind reviewer should see it: no
+// identity attributes and no contact details. Only admins and the owner may
+// read it; nil when the CV does not exist.
+func (s *cvService) GetAnonymized(ctx context.Context, who Searcher, id string) (*dto.AnonymizedCVResponse, error) {
 	cv, err := s.repo.GetCv(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	qcv, err := qdrant.GetFromQdrant(ctx, cv, s.cfg)
+	if !who.Admin && cv.UserID != who.UserID {
+		return nil, ErrCVForbidden
+	}
+
+	text, _ := redact.Anonymize(cv.Summary, knownNames(cv)...)
+	text, _ = redact.New(redact.PolicyStrict...).Redact(text)
+
+	return &dto.AnonymizedCVResponse{
+		ID:    cv.ID,
+		Title: cv.Title,
+		Text:  text,
+	}, nil
+}
+
+const defaultSimilarLimit = 10
+
+// GetSimilar finds the candidates closest to a CV by its stored vector, one
+// CV per candidate; nil when the CV does not exist and ErrCVNoEmbedding when
+// it has no vector.
+func (s *cvService) GetSimilar(ctx context.Context, id string, q dto.SimilarCVsQuery) (*dto.SimilarCVsResponse, error) {
+	cv, err := s.repo.GetCv(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	// s.cfg.Logger.Debugf("summary and filepath:\n-%v\n-%v", cv.Summary, cv.FilePath)
-	return qcv, nil
+
+	limit := q.Limit
+	if limit < 1 {
+		limit = defaultSimilarLimit
+	}
+	// candidates with several CVs collapse to their best one, so ask for more
+	hits, err := s.store.Search(ctx, vectorstore.Query{
+		Like: cv.ID,
+		Filter: vectorstore.Filter{
+			ExcludeIDs:    []string{cv.ID},
+			ExcludeUserID: cv.UserID,
+			JobID:         q.JobID,
+			Since:         q.Since,
+			Until:         q.Until,
+		},
+		Limit:     limit * 3,
+		Threshold: q.Threshold,
+	})
+	if errors.Is(err, vectorstore.ErrNotFound) {
+		return nil, ErrCVNoEmbedding
+	}
+	if err != nil {
+		return nil, fmt.Errorf("similar CV search failed: %w", err)
+	}
+
+	resp := &dto.SimilarCVsResponse{SourceID: cv.ID, Results: []dto.SimilarCV{}}
+	seen := map[string]bool{}
+	for _, h := range hits {
+		if seen[h.UserID] {
+			continue
+		}
+		seen[h.UserID] = true
+		similar := dto.SimilarCV{
+			ID:     h.ID,
+			UserID: h.UserID,
+			JobID:  h.JobID,
+			Title:  h.Title,
+			Score:  h.Score,
+		}
+		if !h.CreatedAt.IsZero() {
+			createdAt := h.CreatedAt
+			similar.CreatedAt = &createdAt
+		}
+		resp.Results = append(resp.Results, similar)
+		if len(resp.Results) == limit {
+			break
+		}
+	}
+	return resp, nil
+}
+
+// knownNames lists the candidate names we already know from their profile.
+func knownNames(cv *entity.CV) []string {
+	if cv.User == nil || cv.User.Profile == nil || cv.User.Profile.FullName == "" {
+		return nil
+	}
+	return []string{cv.User.Profile.FullName}
+}
+
+func contactResponses(cv *entity.CV) []dto.ContactResponse {
+	var out []dto.ContactResponse
+	for _, c := range cv.Contacts {
+		out = append(out, dto.ContactResponse{
+			Kind:   c.Kind,
+			Value:  c.Value,
+			Source: c.Source,
+		})
+	}
+	return out
 }
@@ -6,25 +6,39 @@ import (
 
 	"github.com/GazDuckington/go-gin/internal/config"
 	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
 	"github.com/GazDuckington/go-gin/internal/repository"
 	gemini "github.com/GazDuckington/go-gin/pkgs/genai"
-	"github.com/GazDuckington/go-gin/pkgs/qdrant"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
 )
 
 // CVWorkerService manages background CV evaluations
 type CVWorkerService struct {
-	cfg    *config.Config
-	repo   repository.CVRepository
-	jobs   chan string
-	status sync.Map // map[cvID]string
+	cfg      *config.Config
+	repo     repository.CVRepository
+	evalRepo repository.EvaluationRepository
+	store    vectorstore.VectorStore
+	pipeline *EvaluationPipeline
+	jobs     chan evalJob
+	status   sync.Map // map[cvID]string
+}
+
+// evalJob is one queued evaluation; blind is the per-request flag, a job
+// posting can also force blind mode for all of its applicants.
+type evalJob struct {
+	cvID  string
+	blind bool
 }
 
 // NewCVWorkerService creates and starts the worker
-func NewCVWorkerService(cfg *config.Config, repo repository.CVRepository) *CVWorkerService {
+func NewCVWorkerService(cfg *config.Config, repo repository.CVRepository, evalRepo repository.EvaluationRepository, store vectorstore.VectorStore) *CVWorkerService {
 	s := &CVWorkerService{
-		cfg:  cfg,
-		jobs: make(chan string, 100),
-		repo: repo,
+		cfg:      cfg,
+		jobs:     make(chan evalJob, 100),
+		repo:     repo,
+		evalRepo: evalRepo,
+		store:    store,
+		pipeline: NewEvaluationPipeline(cfg, gemini.Live, gemini.GenerateEmbedding),
 	}
 
 	go s.workerLoop()
@@ -32,9 +46,9 @@ func NewCVWorkerService(cfg *config.Config, repo repository.CVRepository) *CVWor
 }
 
 // EnqueueCV adds a CV to the evaluation queue and returns status info
-func (s *CVWorkerService) EnqueueCV(cvID string) dto.WorkerStatusResponse {
+func (s *CVWorkerService) EnqueueCV(cvID string, blind bool) dto.WorkerStatusResponse {
 	s.status.Store(cvID, "queued")
-	s.jobs <- cvID
+	s.jobs <- evalJob{cvID: cvID, blind: blind}
 
 	return dto.WorkerStatusResponse{
 		ID:     cvID,
@@ -67,7 +81,8 @@ func (s *CVWorkerService) GetStatus(cvID string) dto.WorkerStatusResponse {
 // workerLoop processes jobs asynchronously
 func (s *CVWorkerService) workerLoop() {
 	ctx := context.Background()
-	for cvID := range s.jobs {
+	for job := range s.jobs {
+		cvID := job.cvID
 		s.setState(cvID, "processing", nil)
 
 		cv, err := s.repo.GetCv(ctx, cvID)
@@ -83,27 +98,67 @@ func (s *CVWorkerService) workerLoop() {
 			continue
 		}
 
-		qcv, err := qdrant.GetFromQdrant(ctx, cv, s.cfg)
-		if err != nil {
-			s.cfg.Logger.Warnf("[worker] failed to fetch Qdrant data for CV %s: %v", cvID, err)
-			s.setState(cvID, "error", nil)
+		if cv.NeedsOCR {
+			// evaluating unreadable text only produces garbage scores
+			s.cfg.Logger.Warnf("[worker] CV %s needs OCR, not evaluating", cvID)
+			s.setState(cvID, "needs_ocr", nil)
 			continue
 		}
 
-		result, err := s.evaluateWithGemini(qcv)
+		res := cvResponse(ctx, s.cfg, s.store, cv)
+		res.Contacts = contactResponses(cv)
+		fillParsed(res, cv)
+
+		blind := job.blind || (cv.Job != nil && cv.Job.Blind)
+		var posting *dto.JobResponse
+		if cv.Job != nil {
+			j := toJobResponse(cv.Job)
+			posting = &j
+		}
+		result, err := s.evaluateWithGemini(res, blind, knownNames(cv), posting)
 		if err != nil {
 			s.cfg.Logger.Warnf("[worker] evaluation failed for CV %s: %v", cvID, err)
 			s.setState(cvID, "failed", nil)
 			continue
 		}
 
+		stored := &entity.Evaluation{
+			CVID:            cvID,
+			Model:           gemini.EvalModel,
+			PromptVersion:   gemini.PromptVersion,
+			Blind:           result.BlindMode,
+			CVScores:        result.CVScores,
+			ProjectScores:   result.ProjectScores,
+			CVMatchRate:     result.CVMatchRate,
+			CVFeedback:      result.CVFeedback,
+			ProjectScore:    result.ProjectScore,
+			ProjectFeedback: result.ProjectFeedback,
+			OverallSummary:  result.OverallSummary,
+			ExperienceCheck: result.ExperienceCheck,
+			SkillMatch:      result.SkillMatch,
+			Hybrid:          result.Hybrid,
+			ReviewStatus:    entity.ReviewPending,
+		}
+		if err := s.evalRepo.Create(ctx, stored); err != nil {
+			// the result is still served from memory, it just can't be calibrated
+			s.cfg.Logger.Warnf("[worker] failed to store evaluation for CV %s: %v", cvID, err)
+		} else {
+			result.EvaluationID = stored.ID
+			result.ReviewStatus = stored.ReviewStatus
+		}
+
 		s.setState(cvID, "done", result)
 	}
 }
 
-// evaluateWithGemini is a placeholder for the LLM call
-func (s *CVWorkerService) evaluateWithGemini(cv *dto.CVResponse) (*dto.CVEvaluationResponse, error) {
-	eval, err := gemini.EvaluateCV(context.Background(), cv)
+// evaluateWithGemini runs the CV through the evaluation pipeline
+func (s *CVWorkerService) evaluateWithGemini(cv *dto.CVResponse, blind bool, names []string, job *dto.JobResponse) (*dto.CVEvaluationResponse, error) {
+	eval, err := s.pipeline.Evaluate(context.Background(), EvaluationInput{
+		CV:    cv,
+		Blind: blind,
+		Names: names,
+		Job:   job,
+	})
 	if err != nil {
 		s.cfg.Logger.Warnf("[worker] evaluating via gemini failed: %v", err)
 		return nil, err
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	"github.com/GazDuckington/go-gin/pkgs/embedcache"
+	"gorm.io/gorm"
+)
+
+// NewEmbeddingCache returns the cache EMBEDDING_CACHE asks for, nil when it
+// is "off". Without db a "postgres" cache only keeps embeddings in memory.
+func NewEmbeddingCache(cfg *config.Config, db *gorm.DB) (*embedcache.Cache, error) {
+	var store embedcache.Store
+	switch cfg.EmbeddingCache {
+	case "off":
+		return nil, nil
+	case "memory":
+	case "postgres":
+		if db == nil {
+			cfg.Logger.Warn("[embedcache] no database, caching embeddings in memory only")
+		} else {
+			store = repository.NewEmbeddingCacheRepository(db, cfg)
+		}
+	default:
+		return nil, fmt.Errorf("unknown embedding cache %q, use postgres, memory or off", cfg.EmbeddingCache)
+	}
+
+	return embedcache.New(cfg.EmbeddingModel, cfg.EmbeddingDim, cfg.EmbeddingCacheSize, store, cfg.Logger), nil
+}
@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	"github.com/GazDuckington/go-gin/pkgs/stats"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidHumanScore is returned for unknown criteria or out-of-range scores.
+	ErrInvalidHumanScore = errors.New("invalid human score")
+	// ErrInvalidOverride is returned for an override that changes nothing or
+	// uses unknown criteria or out-of-range scores.
+	ErrInvalidOverride = errors.New("invalid override")
+	// ErrReviewConflict is returned when the evaluation's review state does
+	// not allow the action.
+	ErrReviewConflict = errors.New("review state conflict")
+)
+
+type EvaluationService interface {
+	GetByID(ctx context.Context, id string) (*dto.EvaluationResponse, error)
+	SubmitHumanScores(ctx context.Context, evalID, reviewerID string, req dto.HumanScoresRequest) (*dto.EvaluationResponse, error)
+	Calibration(ctx context.Context) (*dto.CalibrationResponse, error)
+	List(ctx context.Context, reviewStatus string) ([]dto.EvaluationResponse, error)
+	Approve(ctx context.Context, evalID, actorID string, req dto.ApproveEvaluationRequest) (*dto.EvaluationResponse, error)
+	Override(ctx context.Context, evalID, actorID string, req dto.OverrideEvaluationRequest) (*dto.EvaluationResponse, error)
+	Audit(ctx context.Context, evalID string) ([]dto.EvaluationAuditResponse, error)
+}
+
+type evaluationService struct {
+	repo repository.EvaluationRepository
+	cfg  *config.Config
+}
+
+func NewEvaluationService(r repository.EvaluationRepository, cfg *config.Config) EvaluationService {
+	return &evaluationService{repo: r, cfg: cfg}
+}
+
+func toEvaluationResponse(e *entity.Evaluation) *dto.EvaluationResponse {
+	resp := &dto.EvaluationResponse{
+		ID:              e.ID,
+		CVID:            e.CVID,
+		Model:           e.Model,
+		PromptVersion:   e.PromptVersion,
+		Blind:           e.Blind,
+		CVScores:        e.CVScores,
+		ProjectScores:   e.ProjectScores,
+		CVMatchRate:     e.CVMatchRate,
+		CVFeedback:      e.CVFeedback,
+		ProjectScore:    e.ProjectScore,
+		ProjectFeedback: e.ProjectFeedback,
+		OverallSummary:  e.OverallSummary,
+		ExperienceCheck: e.ExperienceCheck,
+		SkillMatch:      e.SkillMatch,
+		Hybrid:          e.Hybrid,
+		ReviewStatus:    e.ReviewStatus,
+		ReviewedBy:      e.ReviewedBy,
+		ReviewedAt:      e.ReviewedAt,
+		CreatedAt:       e.CreatedAt,
+	}
+	if e.ReviewStatus == entity.ReviewOverridden {
+		resp.Override = &dto.OverrideResponse{
+			CVScores:       e.OverrideCVScores,
+			ProjectScores:  e.OverrideProjectScores,
+			CVMatchRate:    e.EffectiveCVMatchRate(),
+			ProjectScore:   e.EffectiveProjectScore(),
+			OverallSummary: e.OverrideSummary,
+		}
+		if e.OverrideReason != nil {
+			resp.Override.Reason = *e.OverrideReason
+		}
+	}
+	for _, h := range e.HumanScores {
+		resp.HumanScores = append(resp.HumanScores, dto.HumanScoreResponse{
+			ReviewerID: h.ReviewerID,
+			Criterion:  h.Criterion,
+			Score:      h.Score,
+		})
+	}
+	return resp
+}
+
+func (s *evaluationService) GetByID(ctx context.Context, id string) (*dto.EvaluationResponse, error) {
+	eval, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toEvaluationResponse(eval), nil
+}
+
+// rubricNames returns the criterion names of the CV and project rubrics.
+func rubricNames() (cv, project map[string]bool) {
+	rubrics := entity.NewDefaultRubrics()
+	cv, project = map[string]bool{}, map[string]bool{}
+	for _, r := range rubrics.CV {
+		cv[r.Name] = true
+	}
+	for _, r := range rubrics.Project {
+		project[r.Name] = true
+	}
+	return cv, project
+}
+
+func (s *evaluationService) SubmitHumanScores(ctx context.Context, evalID, reviewerID string, req dto.HumanScoresRequest) (*dto.EvaluationResponse, error) {
+	cvNames, projectNames := rubricNames()
+
+	scores := make([]entity.HumanScore, 0, len(req.Scores))
+	for criterion, score := range req.Scores {
+		if !cvNames[criterion] && !projectNames[criterion] {
+			return nil, fmt.Errorf("%w: unknown criterion %q", ErrInvalidHumanScore, criterion)
+		}
+		if score < 1 || score > 5 {
+			return nil, fmt.Errorf("%w: %q must be between 1 and 5", ErrInvalidHumanScore, criterion)
+		}
+		scores = append(scores, entity.HumanScore{
+			EvaluationID: evalID,
+			ReviewerID:   reviewerID,
+			Criterion:    criterion,
+			Score:        score,
+		})
+	}
+
+	if _, err := s.repo.FindByID(ctx, evalID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := s.repo.SaveHumanScores(ctx, scores); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, evalID)
+}
+
+// calibrationBucket maps a 1-5 score to low (0), mid (1) or high (2), the
+// granularity a recruiter decision is actually made at.
+func calibrationBucket(score float64) int {
+	switch {
+	case score < 2.5:
+		return 0
+	case score < 3.5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+type scorePairs struct {
+	llm, human []float64
+}
+
+func (p *scorePairs) add(llm, human float64) {
+	p.llm = append(p.llm, llm)
+	p.human = append(p.human, human)
+}
+
+// Calibration compares LLM scores with recruiter scores per model and prompt
+// version, per criterion and over all criteria together. When several
+// recruiters scored the same criterion, their mean is the ground truth.
+func (s *evaluationService) Calibration(ctx context.Context) (*dto.CalibrationResponse, error) {
+	evals, err := s.repo.FindWithHumanScores(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type groupKey struct{ model, prompt string }
+	groups := map[groupKey]map[string]*scorePairs{}
+
+	for i := range evals {
+		e := &evals[i]
+		key := groupKey{e.Model, e.PromptVersion}
+		if groups[key] == nil {
+			groups[key] = map[string]*scorePairs{}
+		}
+
+		sums := map[string]float64{}
+		counts := map[string]int{}
+		for _, h := range e.HumanScores {
+			sums[h.Criterion] += h.Score
+			counts[h.Criterion]++
+		}
+		for criterion, sum := range sums {
+			llm, ok := e.LLMScore(criterion)
+			if !ok {
+				continue
+			}
+			if groups[key][criterion] == nil {
+				groups[key][criterion] = &scorePairs{}
+			}
+			groups[key][criterion].add(llm, sum/float64(counts[criterion]))
+		}
+	}
+
+	resp := &dto.CalibrationResponse{
+		Thresholds: dto.CalibrationThresholds{
+			MinSamples: s.cfg.CalibrationMinSamples,
+			MinKappa:   s.cfg.CalibrationMinKappa,
+			MaxMAE:     s.cfg.CalibrationMaxMAE,
+		},
+		Groups: []dto.CalibrationGroup{},
+	}
+
+	for key, criteria := range groups {
+		group := dto.CalibrationGroup{
+			Model:         key.model,
+			PromptVersion: key.prompt,
+		}
+		all := &scorePairs{}
+		names := make([]string, 0, len(criteria))
+		for name := range criteria {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			p := criteria[name]
+			m := s.metrics(p)
+			m.Criterion = name
+			group.Criteria = append(group.Criteria, m)
+			all.llm = append(all.llm, p.llm...)
+			all.human = append(all.human, p.human...)
+		}
+		group.Overall = s.metrics(all)
+		resp.Groups = append(resp.Groups, group)
+	}
+
+	sort.Slice(resp.Groups, func(i, j int) bool {
+		if resp.Groups[i].Model != resp.Groups[j].Model {
+			return resp.Groups[i].Model < resp.Groups[j].Model
+		}
+		return resp.Groups[i].PromptVersion < resp.Groups[j].PromptVersion
+	})
+	return resp, nil
+}
+
+func (s *evaluationService) metrics(p *scorePairs) dto.CalibrationMetrics {
+	llmBuckets := make([]int, len(p.llm))
+	humanBuckets := make([]int, len(p.human))
+	for i := range p.llm {
+		llmBuckets[i] = calibrationBucket(p.llm[i])
+		humanBuckets[i] = calibrationBucket(p.human[i])
+	}
+
+	m := dto.CalibrationMetrics{
+		Samples:  len(p.llm),
+		MAE:      finite(stats.MeanAbsoluteError(p.llm, p.human)),
+		Spearman: finite(stats.Spearman(p.llm, p.human)),
+		Kappa:    finite(stats.CohenKappa(llmBuckets, humanBuckets, 3)),
+	}
+
+	switch {
+	case m.Samples < s.cfg.CalibrationMinSamples || m.MAE == nil || m.Kappa == nil:
+		m.Verdict = "insufficient_data"
+	case *m.Kappa >= s.cfg.CalibrationMinKappa && *m.MAE <= s.cfg.CalibrationMaxMAE:
+		m.Verdict = "trusted"
+	default:
+		m.Verdict = "not_trusted"
+	}
+	return m
+}
+
+// finite drops NaN so the value serialises as null instead of failing JSON.
+func finite(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+func (s *evaluationService) List(ctx context.Context, reviewStatus string) ([]dto.EvaluationResponse, error) {
+	evals, err := s.repo.FindByReviewStatus(ctx, reviewStatus)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.EvaluationResponse, 0, len(evals))
+	for i := range evals {
+		out = append(out, *toEvaluationResponse(&evals[i]))
+	}
+	return out, nil
+}
+
+// Approve signs off on the LLM result as it is. Only pending evaluations can
+// be approved; an overridden one already carries the reviewer's decision.
+func (s *evaluationService) Approve(ctx context.Context, evalID, actorID string, req dto.ApproveEvaluationRequest) (*dto.EvaluationResponse, error) {
+	eval, err := s.repo.FindByID(ctx, evalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if eval.ReviewStatus != entity.ReviewPending {
+		return nil, fmt.Errorf("%w: evaluation is %s", ErrReviewConflict, eval.ReviewStatus)
+	}
+
+	readStatus, readAt := eval.ReviewStatus, eval.UpdatedAt
+	now := time.Now()
+	audit := entity.EvaluationAudit{
+		EvaluationID: eval.ID,
+		ActorID:      actorID,
+		Action:       "approve",
+		Field:        "review_status",
+		OldValue:     eval.ReviewStatus,
+		NewValue:     entity.ReviewApproved,
+		Reason:       req.Comment,
+	}
+	eval.ReviewStatus = entity.ReviewApproved
+	eval.ReviewedBy = &actorID
+	eval.ReviewedAt = &now
+
+	if err := s.saveReview(ctx, eval, readStatus, readAt, []entity.EvaluationAudit{audit}); err != nil {
+		return nil, err
+	}
+	return toEvaluationResponse(eval), nil
+}
+
+// Override replaces individual criterion scores and/or the summary. The LLM
+// output is left untouched and every changed field gets an audit row with
+// its previous effective value; an override that changes nothing is rejected.
+func (s *evaluationService) Override(ctx context.Context, evalID, actorID string, req dto.OverrideEvaluationRequest) (*dto.EvaluationResponse, error) {
+	if len(req.CVScores) == 0 && len(req.ProjectScores) == 0 && req.OverallSummary == nil {
+		return nil, fmt.Errorf("%w: nothing to override", ErrInvalidOverride)
+	}
+	cvNames, projectNames := rubricNames()
+	if err := validateOverrideScores(req.CVScores, cvNames); err != nil {
+		return nil, err
+	}
+	if err := validateOverrideScores(req.ProjectScores, projectNames); err != nil {
+		return nil, err
+	}
+
+	eval, err := s.repo.FindByID(ctx, evalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	readStatus, readAt := eval.ReviewStatus, eval.UpdatedAt
+
+	var audits []entity.EvaluationAudit
+	record := func(field, oldValue, newValue string) {
+		audits = append(audits, entity.EvaluationAudit{
+			EvaluationID: eval.ID,
+			ActorID:      actorID,
+			Action:       "override",
+			Field:        field,
+			OldValue:     oldValue,
+			NewValue:     newValue,
+			Reason:       req.Reason,
+		})
+	}
+
+	eval.OverrideCVScores = applyOverride("cv_scores", eval.EffectiveCVScores(), eval.OverrideCVScores, req.CVScores, record)
+	eval.OverrideProjectScores = applyOverride("project_scores", eval.EffectiveProjectScores(), eval.OverrideProjectScores, req.ProjectScores, record)
+	if req.OverallSummary != nil && *req.OverallSummary != eval.EffectiveSummary() {
+		record("overall_summary", eval.EffectiveSummary(), *req.OverallSummary)
+		eval.OverrideSummary = req.OverallSummary
+	}
+	if len(audits) == 0 {
+		return nil, fmt.Errorf("%w: nothing differs from the current evaluation", ErrInvalidOverride)
+	}
+	if eval.ReviewStatus != entity.ReviewOverridden {
+		record("review_status", eval.ReviewStatus, entity.ReviewOverridden)
+	}
+
+	now := time.Now()
+	eval.ReviewStatus = entity.ReviewOverridden
+	eval.ReviewedBy = &actorID
+	eval.ReviewedAt = &now
+	eval.OverrideReason = &req.Reason
+
+	if err := s.saveReview(ctx, eval, readStatus, readAt, audits); err != nil {
+		return nil, err
+	}
+	return toEvaluationResponse(eval), nil
+}
+
+// saveReview reports a review that lost the race as a conflict.
+func (s *evaluationService) saveReview(ctx context.Context, eval *entity.Evaluation, readStatus string, readAt time.Time, audits []entity.EvaluationAudit) error {
+	err := s.repo.SaveReview(ctx, eval, readStatus, readAt, audits)
+	if errors.Is(err, repository.ErrStaleReview) {
+		return fmt.Errorf("%w: %v", ErrReviewConflict, err)
+	}
+	return err
+}
+
+func validateOverrideScores(scores map[string]float64, known map[string]bool) error {
+	for criterion, score := range scores {
+		if !known[criterion] {
+			return fmt.Errorf("%w: unknown criterion %q", ErrInvalidOverride, criterion)
+		}
+		if score < 1 || score > 5 {
+			return fmt.Errorf("%w: %q must be between 1 and 5", ErrInvalidOverride, criterion)
+		}
+	}
+	return nil
+}
+
+// applyOverride merges changes into the existing overrides, recording each
+// criterion whose effective score actually changes.
+func applyOverride(prefix string, effective, existing entity.ScoreMap, changes map[string]float64, record func(field, oldValue, newValue string)) entity.ScoreMap {
+	if len(changes) == 0 {
+		return existing
+	}
+	out := entity.ScoreMap{}
+	for k, v := range existing {
+		out[k] = v
+	}
+
+	names := make([]string, 0, len(changes))
+	for k := range changes {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		old, had := effective[k]
+		if had && old == changes[k] {
+			continue
+		}
+		oldValue := ""
+		if had {
+			oldValue = strconv.FormatFloat(old, 'g', -1, 64)
+		}
+		record(prefix+"."+k, oldValue, strconv.FormatFloat(changes[k], 'g', -1, 64))
+		out[k] = changes[k]
+	}
+	return out
+}
+
+func (s *evaluationService) Audit(ctx context.Context, evalID string) ([]dto.EvaluationAuditResponse, error) {
+	audits, err := s.repo.FindAudits(ctx, evalID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.EvaluationAuditResponse, 0, len(audits))
+	for _, a := range audits {
+		out = append(out, dto.EvaluationAuditResponse{
+			ID:        a.ID,
+			ActorID:   a.ActorID,
+			Action:    a.Action,
+			Field:     a.Field,
+			OldValue:  a.OldValue,
+			NewValue:  a.NewValue,
+			Reason:    a.Reason,
+			CreatedAt: a.CreatedAt,
+		})
+	}
+	return out, nil
+}
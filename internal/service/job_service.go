@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	"github.com/GazDuckington/go-gin/pkgs/skills"
+	"gorm.io/gorm"
+)
+
+type JobService interface {
+	Create(ctx context.Context, createdBy string, req dto.CreateJobRequest) (*dto.JobResponse, error)
+	GetAll(ctx context.Context) ([]dto.JobResponse, error)
+	GetByID(ctx context.Context, id string) (*dto.JobResponse, error)
+}
+
+type jobService struct {
+	repo repository.JobRepository
+}
+
+func NewJobService(r repository.JobRepository) JobService {
+	return &jobService{repo: r}
+}
+
+func toJobResponse(j *entity.Job) dto.JobResponse {
+	return dto.JobResponse{
+		ID:          j.ID,
+		Title:       j.Title,
+		Description: j.Description,
+		Blind:       j.Blind,
+
+		RequiredSkills: j.RequiredSkills,
+	}
+}
+
+func (s *jobService) Create(ctx context.Context, createdBy string, req dto.CreateJobRequest) (*dto.JobResponse, error) {
+	job := &entity.Job{
+		Title:       req.Title,
+		Description: req.Description,
+		Blind:       req.Blind,
+		CreatedBy:   createdBy,
+
+		RequiredSkills: skills.Default().Normalize(req.RequiredSkills),
+	}
+	if job.RequiredSkills == nil {
+		job.RequiredSkills = []string{}
+	}
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	resp := toJobResponse(job)
+	return &resp, nil
+}
+
+func (s *jobService) GetAll(ctx context.Context) ([]dto.JobResponse, error) {
+	jobs, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]dto.JobResponse, 0, len(jobs))
+	for i := range jobs {
+		out = append(out, toJobResponse(&jobs[i]))
+	}
+	return out, nil
+}
+
+func (s *jobService) GetByID(ctx context.Context, id string) (*dto.JobResponse, error) {
+	job, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	resp := toJobResponse(job)
+	return &resp, nil
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	gemini "github.com/GazDuckington/go-gin/pkgs/genai"
+	"github.com/GazDuckington/go-gin/pkgs/scoring"
+	"github.com/GazDuckington/go-gin/pkgs/stats"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+	"gorm.io/gorm"
+)
+
+// Components of a job ranking, as used in RANKING_WEIGHTS.
+const (
+	RankEvaluation = "evaluation" // weighted CV rubric scores, after overrides
+	RankProject    = "project"    // weighted project rubric scores, after overrides
+	RankSimilarity = "similarity" // CV to job description embedding similarity
+)
+
+// DefaultRankingWeights is used when RANKING_WEIGHTS cannot be parsed.
+const DefaultRankingWeights = "evaluation=0.6,project=0.1,similarity=0.3"
+
+var rankComponents = []string{RankEvaluation, RankProject, RankSimilarity}
+
+type RankingService interface {
+	// RankJob ranks every CV submitted to a job; nil when the job does not exist.
+	RankJob(ctx context.Context, jobID string, q dto.JobRankingQuery) (*dto.JobRankingResponse, error)
+}
+
+type rankingService struct {
+	jobRepo  repository.JobRepository
+	cvRepo   repository.CVRepository
+	evalRepo repository.EvaluationRepository
+	store    vectorstore.VectorStore
+	cfg      *config.Config
+	embed    EmbedFunc
+	weights  scoring.Weights
+}
+
+func NewRankingService(jobRepo repository.JobRepository, cvRepo repository.CVRepository, evalRepo repository.EvaluationRepository, store vectorstore.VectorStore, cfg *config.Config) RankingService {
+	weights, err := scoring.ParseWeights(cfg.RankingWeights)
+	if err != nil {
+		cfg.Logger.Warnf("[ranking] invalid RANKING_WEIGHTS, using %s: %v", DefaultRankingWeights, err)
+		weights, _ = scoring.ParseWeights(DefaultRankingWeights)
+	}
+	return &rankingService{
+		jobRepo:  jobRepo,
+		cvRepo:   cvRepo,
+		evalRepo: evalRepo,
+		store:    store,
+		cfg:      cfg,
+		embed:    gemini.GenerateEmbedding,
+		weights:  weights,
+	}
+}
+
+// rankedCV carries what ties are broken on next to the response row.
+type rankedCV struct {
+	dto.RankedCV
+	evaluation float64
+	similarity float64
+	reviewed   bool
+}
+
+func (s *rankingService) RankJob(ctx context.Context, jobID string, q dto.JobRankingQuery) (*dto.JobRankingResponse, error) {
+	job, err := s.jobRepo.FindByID(ctx, jobID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cvs, err := s.cvRepo.FindByJob(ctx, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(cvs))
+	for i, cv := range cvs {
+		ids[i] = cv.ID
+	}
+
+	evals, err := s.evalRepo.LatestForCVs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[string]*entity.Evaluation, len(evals))
+	for i := range evals {
+		latest[evals[i].CVID] = &evals[i]
+	}
+
+	similarity := s.similarities(ctx, job, ids)
+
+	minScore := s.cfg.RankingMinScore
+	if q.MinScore != nil {
+		minScore = *q.MinScore
+	}
+	resp := &dto.JobRankingResponse{
+		JobID:    job.ID,
+		Title:    job.Title,
+		Weights:  s.weights,
+		MinScore: minScore,
+		Top:      q.Top,
+		Results:  []dto.RankedCV{},
+	}
+
+	rows := make([]rankedCV, 0, len(cvs))
+	for _, cv := range cvs {
+		row := rankedCV{RankedCV: dto.RankedCV{
+			CVID:        cv.ID,
+			UserID:      cv.UserID,
+			Title:       cv.Title,
+			SubmittedAt: cv.CreatedAt,
+		}}
+		if !job.Blind {
+			if names := knownNames(&cv); len(names) > 0 {
+				row.Candidate = names[0]
+			}
+		}
+
+		scores := map[string]float64{}
+		if e, ok := latest[cv.ID]; ok {
+			row.EvaluationID = e.ID
+			row.ReviewStatus = e.ReviewStatus
+			row.Overridden = e.ReviewStatus == entity.ReviewOverridden
+			row.reviewed = e.ReviewStatus != entity.ReviewPending
+			cvScore, project, hasProject := evaluationScores(e)
+			scores[RankEvaluation] = cvScore
+			if hasProject {
+				scores[RankProject] = project
+			}
+			row.evaluation = cvScore
+		} else if q.RequireEvaluation {
+			continue
+		}
+		if sim, ok := similarity[cv.ID]; ok {
+			scores[RankSimilarity] = sim
+			row.similarity = sim
+		}
+
+		blended := scoring.Blend(s.weights, scores)
+		row.Score = blended.Score
+		row.Components = blended.Contributions
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return rankedBefore(rows[i], rows[j]) })
+
+	for i := range rows {
+		row := rows[i].RankedCV
+		row.Rank = i + 1
+		// a score made of similarity alone is not comparable, so never shortlist it
+		row.Shortlisted = row.EvaluationID != "" && row.Score >= minScore && (q.Top == 0 || resp.Shortlisted < q.Top)
+		if row.Shortlisted {
+			resp.Shortlisted++
+		}
+		resp.Results = append(resp.Results, row)
+	}
+	resp.Total = len(resp.Results)
+	return resp, nil
+}
+
+// evaluationScores are the evaluation's CV and project scores on 0-1, with
+// reviewer overrides applied. Without per-criterion scores the LLM's own
+// aggregates are used; hasProject is false when there is no project score.
+func evaluationScores(e *entity.Evaluation) (cv, project float64, hasProject bool) {
+	rubrics := entity.NewDefaultRubrics()
+	cv = e.CVMatchRate
+	if v, ok := entity.WeightedScore(rubrics.CV, e.EffectiveCVScores()); ok {
+		cv = v / 5
+	}
+	project, hasProject = e.ProjectScore/5, e.ProjectScore > 0
+	if v, ok := entity.WeightedScore(rubrics.Project, e.EffectiveProjectScores()); ok {
+		project, hasProject = v/5, true
+	}
+	return clamp01(cv), clamp01(project), hasProject
+}
+
+// similarities compares the job description with each CV's stored vector.
+// Without a description, or when embedding fails, the component is skipped.
+func (s *rankingService) similarities(ctx context.Context, job *entity.Job, ids []string) map[string]float64 {
+	out := map[string]float64{}
+	if s.weights[RankSimilarity] == 0 || job.Description == "" || len(ids) == 0 {
+		return out
+	}
+	jobVector, err := s.embed(ctx, job.Title+"\n"+job.Description)
+	if err != nil {
+		s.cfg.Logger.Warnf("[ranking] cannot embed job %s, ranking without similarity: %v", job.ID, err)
+		return out
+	}
+	points, err := s.store.Get(ctx, ids...)
+	if err != nil {
+		s.cfg.Logger.Warnf("[ranking] cannot load CV vectors for job %s, ranking without similarity: %v", job.ID, err)
+		return out
+	}
+	for _, p := range points {
+		if sim := stats.Cosine(p.Vector, jobVector); !math.IsNaN(sim) {
+			out[p.ID] = clamp01(sim)
+		}
+	}
+	return out
+}
+
+// rankedBefore puts evaluated CVs first, since Blend renormalizes over the
+// components present and an unevaluated CV would otherwise be scored on
+// similarity alone. Then it orders by score; ties go to the better
+// evaluation, then the closer match, then reviewed over pending, then the
+// earlier submission.
+func rankedBefore(a, b rankedCV) bool {
+	switch {
+	case (a.EvaluationID != "") != (b.EvaluationID != ""):
+		return a.EvaluationID != ""
+	case a.Score != b.Score:
+		return a.Score > b.Score
+	case a.evaluation != b.evaluation:
+		return a.evaluation > b.evaluation
+	case a.similarity != b.similarity:
+		return a.similarity > b.similarity
+	case a.reviewed != b.reviewed:
+		return a.reviewed
+	case !a.SubmittedAt.Equal(b.SubmittedAt):
+		return a.SubmittedAt.Before(b.SubmittedAt)
+	default:
+		return a.CVID < b.CVID
+	}
+}
+
+// WriteRankingCSV writes the shortlisted applicants of a ranking as CSV.
+func WriteRankingCSV(w io.Writer, r *dto.JobRankingResponse) error {
+	cw := csv.NewWriter(w)
+	header := []string{"rank", "cv_id", "user_id", "candidate", "title", "score"}
+	header = append(header, rankComponents...)
+	header = append(header, "review_status", "overridden", "evaluation_id", "submitted_at")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range r.Results {
+		if !row.Shortlisted {
+			continue
+		}
+		record := []string{
+			strconv.Itoa(row.Rank), row.CVID, row.UserID, csvText(row.Candidate), csvText(row.Title),
+			formatScore(row.Score, true),
+		}
+		for _, name := range rankComponents {
+			record = append(record, componentScore(row.Components, name))
+		}
+		record = append(record,
+			row.ReviewStatus,
+			strconv.FormatBool(row.Overridden),
+			row.EvaluationID,
+			row.SubmittedAt.UTC().Format(time.RFC3339),
+		)
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvText stops spreadsheets from running user supplied text as a formula by
+// prefixing cells that start with a formula character with a quote.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func componentScore(cs []scoring.Contribution, name string) string {
+	for _, c := range cs {
+		if c.Scorer == name {
+			return formatScore(c.Score, !c.Skipped)
+		}
+	}
+	return ""
+}
+
+func formatScore(v float64, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%.4f", v)
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/pkgs/redact"
+)
+
+// newRedactor builds the PII redactor for the configured policy. An invalid
+// policy falls back to the standard one rather than sending raw text out.
+func newRedactor(cfg *config.Config) *redact.Redactor {
+	kinds, err := redact.ParsePolicy(cfg.RedactionPolicy)
+	if err != nil {
+		cfg.Logger.Warnf("invalid REDACTION_POLICY %q, using standard: %v", cfg.RedactionPolicy, err)
+		kinds = redact.PolicyStandard
+	}
+	return redact.New(kinds...)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	gemini "github.com/GazDuckington/go-gin/pkgs/genai"
+	"github.com/GazDuckington/go-gin/pkgs/redact"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+	"gorm.io/gorm"
+)
+
+// ReindexCheckpoint is the progress of a reindex, saved after every batch so
+// an interrupted run resumes where it stopped.
+type ReindexCheckpoint struct {
+	Target    string              `json:"target"`
+	Model     string              `json:"model"`
+	Dim       int                 `json:"dim"`
+	After     repository.CVCursor `json:"after"`
+	Indexed   int                 `json:"indexed"`
+	Skipped   int                 `json:"skipped"`
+	Failed    []string            `json:"failed,omitempty"`
+	StartedAt time.Time           `json:"started_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// Reindexer re-embeds every CV into a vector store with the configured
+// embedding model.
+type Reindexer struct {
+	cfg      *config.Config
+	repo     repository.CVRepository
+	store    vectorstore.VectorStore
+	redactor *redact.Redactor
+	embed    EmbedBatchFunc
+}
+
+func NewReindexer(cfg *config.Config, repo repository.CVRepository, store vectorstore.VectorStore) *Reindexer {
+	return &Reindexer{
+		cfg:      cfg,
+		repo:     repo,
+		store:    store,
+		redactor: newRedactor(cfg),
+		embed:    gemini.GenerateEmbeddings,
+	}
+}
+
+// Run embeds the CVs after the checkpoint in batches into the store, named
+// target in the checkpoint, until none are left; CVs submitted meanwhile are
+// picked up too. A checkpoint for another target or model starts over.
+// CVs that fail are recorded and retried first on the next run.
+func (r *Reindexer) Run(ctx context.Context, target, checkpointPath string, batchSize int) (*ReindexCheckpoint, error) {
+	cp, err := loadCheckpoint(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+	if cp == nil || cp.Target != target || cp.Model != r.cfg.EmbeddingModel || cp.Dim != r.cfg.EmbeddingDim {
+		if cp != nil {
+			r.cfg.Logger.Infof("[reindex] checkpoint is for %s (%s, %d), starting over", cp.Target, cp.Model, cp.Dim)
+		}
+		cp = &ReindexCheckpoint{
+			Target:    target,
+			Model:     r.cfg.EmbeddingModel,
+			Dim:       r.cfg.EmbeddingDim,
+			StartedAt: time.Now().UTC(),
+		}
+	}
+
+	if len(cp.Failed) > 0 {
+		retry := cp.Failed
+		cp.Failed = nil
+		r.cfg.Logger.Infof("[reindex] retrying %d failed CVs", len(retry))
+		for _, id := range retry {
+			cv, err := r.repo.GetCv(ctx, id)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				cp.Skipped++ // deleted since
+				continue
+			}
+			if err != nil {
+				r.cfg.Logger.Warnf("[reindex] CV %s: %v", id, err)
+				cp.Failed = append(cp.Failed, id)
+				continue
+			}
+			if err := r.index(ctx, cv, cp); err != nil {
+				return cp, err
+			}
+		}
+		if err := saveCheckpoint(checkpointPath, cp); err != nil {
+			return cp, err
+		}
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return cp, err
+		}
+		cvs, err := r.repo.ListAfter(ctx, cp.After, batchSize)
+		if err != nil {
+			return cp, err
+		}
+		if len(cvs) == 0 {
+			return cp, nil
+		}
+
+		for i := range cvs {
+			if err := r.index(ctx, &cvs[i], cp); err != nil {
+				return cp, err
+			}
+		}
+		last := cvs[len(cvs)-1]
+		cp.After = repository.CVCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		if err := saveCheckpoint(checkpointPath, cp); err != nil {
+			return cp, err
+		}
+		r.cfg.Logger.Infof("[reindex] %d indexed, %d skipped, %d failed", cp.Indexed, cp.Skipped, len(cp.Failed))
+	}
+}
+
+// index embeds one CV into the store. Embedding failures are recorded in the
+// checkpoint; only a failing store stops the run.
+func (r *Reindexer) index(ctx context.Context, cv *entity.CV, cp *ReindexCheckpoint) error {
+	if strings.TrimSpace(cv.Summary) == "" {
+		cp.Skipped++
+		return nil
+	}
+	vector, chunks, err := embedCV(ctx, r.redactor, r.embed, r.cfg.ChunkMaxChars, cv.Summary)
+	if err != nil {
+		r.cfg.Logger.Warnf("[reindex] cannot embed CV %s: %v", cv.ID, err)
+		cp.Failed = append(cp.Failed, cv.ID)
+		return nil
+	}
+	if err := r.store.Upsert(ctx, cvPoint(cv, vector, chunks)); err != nil {
+		return fmt.Errorf("%s upsert of CV %s failed: %w", r.store.Name(), cv.ID, err)
+	}
+	cp.Indexed++
+	return nil
+}
+
+func loadCheckpoint(path string) (*ReindexCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp ReindexCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	return &cp, nil
+}
+
+// saveCheckpoint writes through a temporary file so a crash never leaves a
+// half written checkpoint.
+func saveCheckpoint(path string, cp *ReindexCheckpoint) error {
+	cp.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/pkgs/cvparse"
+	"github.com/GazDuckington/go-gin/pkgs/skills"
+	"github.com/GazDuckington/go-gin/pkgs/stats"
+)
+
+// Scorer is one signal blended into the hybrid score. Scores are between 0
+// and 1; ok is false when the scorer does not apply to this CV, e.g. there is
+// no job to compare with.
+type Scorer interface {
+	Name() string
+	Score(ctx context.Context, in ScoreInput) (score float64, ok bool, err error)
+}
+
+// ScoreInput is what scorers get: the CV, the job it applies to (nil when
+// none), the LLM evaluation and what the pipeline already computed.
+type ScoreInput struct {
+	CV         *dto.CVResponse
+	Job        *dto.JobResponse
+	Eval       *dto.CVEvaluationResponse
+	Experience cvparse.ExperienceYears
+	SkillMatch *skills.MatchResult
+}
+
+// EmbedFunc turns text into an embedding vector.
+type EmbedFunc func(ctx context.Context, text string) ([]float32, error)
+
+// EmbedBatchFunc turns texts into embedding vectors, one per text in order.
+type EmbedBatchFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// Names of the built-in scorers, as used in SCORE_WEIGHTS.
+const (
+	ScorerLLM        = "llm"
+	ScorerSkills     = "skills"
+	ScorerKeywords   = "keywords"
+	ScorerSimilarity = "similarity"
+	ScorerExperience = "experience"
+)
+
+// DefaultScorers are the built-in scorers; embed may be nil, which disables
+// the similarity scorer.
+func DefaultScorers(embed EmbedFunc) []Scorer {
+	return []Scorer{
+		llmScorer{},
+		skillScorer{},
+		keywordScorer{},
+		similarityScorer{embed: embed},
+		experienceScorer{},
+	}
+}
+
+// llmScorer is the LLM's weighted CV match rate.
+type llmScorer struct{}
+
+func (llmScorer) Name() string { return ScorerLLM }
+
+func (llmScorer) Score(_ context.Context, in ScoreInput) (float64, bool, error) {
+	if in.Eval == nil {
+		return 0, false, nil
+	}
+	return clamp01(in.Eval.CVMatchRate), true, nil
+}
+
+// skillScorer is the share of the job's required skills found in the CV.
+type skillScorer struct{}
+
+func (skillScorer) Name() string { return ScorerSkills }
+
+func (skillScorer) Score(_ context.Context, in ScoreInput) (float64, bool, error) {
+	if in.SkillMatch == nil || len(in.SkillMatch.Required) == 0 {
+		return 0, false, nil
+	}
+	return in.SkillMatch.Score, true, nil
+}
+
+// keywordScorer is the share of skills named in the job description that the
+// CV mentions, for jobs without an explicit required skill list.
+type keywordScorer struct{}
+
+func (keywordScorer) Name() string { return ScorerKeywords }
+
+func (keywordScorer) Score(_ context.Context, in ScoreInput) (float64, bool, error) {
+	if in.Job == nil || in.Job.Description == "" {
+		return 0, false, nil
+	}
+	taxonomy := skills.Default()
+	var wanted []string
+	for _, s := range taxonomy.Find(in.Job.Description) {
+		wanted = append(wanted, s.Name)
+	}
+	if len(wanted) == 0 {
+		return 0, false, nil
+	}
+	found := in.CV.Skills
+	if len(found) == 0 {
+		found = taxonomy.Find(in.CV.Summary)
+	}
+	return taxonomy.Match(wanted, found, in.CV.Summary).Score, true, nil
+}
+
+// similarityScorer is the cosine similarity of the CV and job description
+// embeddings. Unrelated texts rarely go below zero, so negatives count as 0.
+type similarityScorer struct {
+	embed EmbedFunc
+}
+
+func (similarityScorer) Name() string { return ScorerSimilarity }
+
+func (s similarityScorer) Score(ctx context.Context, in ScoreInput) (float64, bool, error) {
+	if s.embed == nil || in.Job == nil || in.Job.Description == "" || len(in.CV.Embedding) == 0 {
+		return 0, false, nil
+	}
+	job, err := s.embed(ctx, in.Job.Title+"\n"+in.Job.Description)
+	if err != nil {
+		return 0, false, err
+	}
+	sim := stats.Cosine(in.CV.Embedding, job)
+	if math.IsNaN(sim) {
+		return 0, false, nil
+	}
+	return clamp01(sim), true, nil
+}
+
+// experienceScorer maps computed years of experience onto the rubric's 1-5
+// experience scale, rescaled to 0-1.
+type experienceScorer struct{}
+
+func (experienceScorer) Name() string { return ScorerExperience }
+
+func (experienceScorer) Score(_ context.Context, in ScoreInput) (float64, bool, error) {
+	if in.Experience.TotalMonths == 0 {
+		return 0, false, nil
+	}
+	years := in.Experience.RelevantYears
+	if in.Experience.RelevantMonths == 0 {
+		years = in.Experience.TotalYears
+	}
+	return float64(cvparse.ExperienceScore(years)-1) / 4, true, nil
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
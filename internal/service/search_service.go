@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/GazDuckington/go-gin/internal/config"
+	"github.com/GazDuckington/go-gin/internal/models/dto"
+	"github.com/GazDuckington/go-gin/internal/repository"
+	gemini "github.com/GazDuckington/go-gin/pkgs/genai"
+	"github.com/GazDuckington/go-gin/pkgs/stats"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidSearch is returned for a search without a query or job.
+	ErrInvalidSearch = errors.New("invalid search")
+	// ErrJobNotFound is returned when searching by an unknown job.
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// Search modes.
+const (
+	SearchHybrid  = "hybrid"
+	SearchVector  = "vector"
+	SearchKeyword = "keyword"
+)
+
+const defaultSearchPageSize = 20
+
+// Searcher is who runs a search; only admins see other users' CVs.
+type Searcher struct {
+	UserID string
+	Admin  bool
+}
+
+type SearchService interface {
+	SearchCVs(ctx context.Context, who Searcher, req dto.SearchCVsRequest) (*dto.CVSearchResponse, error)
+}
+
+type searchService struct {
+	cvRepo  repository.CVRepository
+	jobRepo repository.JobRepository
+	store   vectorstore.VectorStore
+	cfg     *config.Config
+	embed   EmbedFunc
+}
+
+func NewSearchService(cvRepo repository.CVRepository, jobRepo repository.JobRepository, store vectorstore.VectorStore, cfg *config.Config) SearchService {
+	return &searchService{cvRepo: cvRepo, jobRepo: jobRepo, store: store, cfg: cfg, embed: gemini.GenerateEmbedding}
+}
+
+// searchText is what a search looks for: text to embed and a
+// websearch_to_tsquery query for the keyword side.
+type searchText struct {
+	semantic string
+	keywords string
+}
+
+func (s *searchService) SearchCVs(ctx context.Context, who Searcher, req dto.SearchCVsRequest) (*dto.CVSearchResponse, error) {
+	text, err := s.searchText(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = SearchHybrid
+	}
+	page, size := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultSearchPageSize
+	}
+	offset := (page - 1) * size
+	resp := &dto.CVSearchResponse{Results: []dto.CVSearchHit{}, Mode: mode, Page: page, PageSize: size}
+
+	// both sides are ranked from the top so they can be fused; one extra
+	// result tells whether there is a next page
+	depth := offset + size + 1
+	if depth > s.cfg.SearchMaxCandidates {
+		depth = s.cfg.SearchMaxCandidates
+	}
+	if depth <= offset {
+		return resp, nil
+	}
+	owner := who.UserID
+	if who.Admin {
+		owner = ""
+	}
+
+	var (
+		wg                    sync.WaitGroup
+		vectorHits            []vectorstore.Hit
+		keywordHits           []repository.CVKeywordHit
+		vectorErr, keywordErr error
+	)
+	if mode != SearchKeyword {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vectorHits, vectorErr = s.vectorSearch(ctx, text.semantic, owner, depth, req.Threshold)
+		}()
+	}
+	if mode != SearchVector {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			keywordHits, keywordErr = s.cvRepo.KeywordSearch(ctx, repository.KeywordQuery{
+				Query:  text.keywords,
+				UserID: owner,
+				Limit:  depth,
+			})
+		}()
+	}
+	wg.Wait()
+
+	switch {
+	case vectorErr != nil && keywordErr != nil:
+		return nil, errors.Join(vectorErr, keywordErr)
+	case vectorErr != nil && mode == SearchVector:
+		return nil, vectorErr
+	case keywordErr != nil && mode == SearchKeyword:
+		return nil, fmt.Errorf("keyword search failed: %w", keywordErr)
+	case vectorErr != nil:
+		// a hybrid search still works on one side
+		s.cfg.Logger.Warnf("[search] vector side failed, keyword results only: %v", vectorErr)
+	case keywordErr != nil:
+		s.cfg.Logger.Warnf("[search] keyword side failed, vector results only: %v", keywordErr)
+	}
+
+	hits := s.fuse(req, vectorHits, keywordHits)
+	// a single side keeps its own score, fusion only matters for hybrid
+	for i := range hits {
+		switch mode {
+		case SearchVector:
+			hits[i].Score = hits[i].VectorScore
+		case SearchKeyword:
+			hits[i].Score = hits[i].KeywordScore
+		}
+	}
+
+	if offset >= len(hits) {
+		return resp, nil
+	}
+	hits = hits[offset:]
+	if len(hits) > size {
+		hits = hits[:size]
+		resp.HasMore = true
+	}
+	resp.Results = hits
+	return resp, nil
+}
+
+func (s *searchService) vectorSearch(ctx context.Context, text, owner string, limit int, threshold float64) ([]vectorstore.Hit, error) {
+	vector, err := s.embed(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed search query: %w", err)
+	}
+	hits, err := s.store.Search(ctx, vectorstore.Query{
+		Vector:    vector,
+		Aggregate: chunkAggregation(s.cfg),
+		Filter:    vectorstore.Filter{UserID: owner},
+		Limit:     limit,
+		Threshold: threshold,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("vector search failed: %w", err)
+	}
+	return hits, nil
+}
+
+// chunkAggregation is how CHUNK_AGGREGATE scores a CV from its chunks;
+// "none" and unknown modes compare whole CV vectors.
+func chunkAggregation(cfg *config.Config) vectorstore.Aggregation {
+	switch cfg.ChunkAggregate {
+	case vectorstore.AggregateMax, vectorstore.AggregateMean:
+		return vectorstore.Aggregation{Mode: cfg.ChunkAggregate, TopK: cfg.ChunkTopK}
+	default:
+		return vectorstore.Aggregation{}
+	}
+}
+
+// fuse merges both rankings with reciprocal rank fusion. With one side
+// empty the order is simply that side's.
+func (s *searchService) fuse(req dto.SearchCVsRequest, vectorHits []vectorstore.Hit, keywordHits []repository.CVKeywordHit) []dto.CVSearchHit {
+	vectorWeight, keywordWeight := s.cfg.SearchVectorWeight, s.cfg.SearchKeywordWeight
+	if req.VectorWeight != nil {
+		vectorWeight = *req.VectorWeight
+	}
+	if req.KeywordWeight != nil {
+		keywordWeight = *req.KeywordWeight
+	}
+
+	byID := map[string]*dto.CVSearchHit{}
+	vectorIDs := make([]string, 0, len(vectorHits))
+	for _, h := range vectorHits {
+		byID[h.ID] = &dto.CVSearchHit{
+			ID:          h.ID,
+			UserID:      h.UserID,
+			Title:       h.Title,
+			ContentType: h.ContentType,
+			VectorScore: h.Score,
+		}
+		vectorIDs = append(vectorIDs, h.ID)
+	}
+	keywordIDs := make([]string, 0, len(keywordHits))
+	for _, h := range keywordHits {
+		hit, ok := byID[h.ID]
+		if !ok {
+			hit = &dto.CVSearchHit{ID: h.ID, UserID: h.UserID, Title: h.Title, ContentType: h.ContentType}
+			byID[h.ID] = hit
+		}
+		hit.KeywordScore = h.Rank
+		keywordIDs = append(keywordIDs, h.ID)
+	}
+
+	fused := stats.ReciprocalRankFusion(s.cfg.SearchRRFK,
+		stats.RankedList{IDs: vectorIDs, Weight: vectorWeight},
+		stats.RankedList{IDs: keywordIDs, Weight: keywordWeight},
+	)
+	out := make([]dto.CVSearchHit, 0, len(fused))
+	for _, f := range fused {
+		hit := byID[f.ID]
+		hit.Score = f.Score
+		hit.VectorRank, hit.KeywordRank = f.Ranks[0], f.Ranks[1]
+		out = append(out, *hit)
+	}
+	return out
+}
+
+// searchText is the free-text query, or the job's title and description with
+// its required skills as keywords.
+func (s *searchService) searchText(ctx context.Context, req dto.SearchCVsRequest) (searchText, error) {
+	query := strings.TrimSpace(req.Query)
+	if req.JobID == "" {
+		if query == "" {
+			return searchText{}, fmt.Errorf("%w: query or job_id is required", ErrInvalidSearch)
+		}
+		return searchText{semantic: query, keywords: keywordQuery(query)}, nil
+	}
+	if query != "" {
+		return searchText{}, fmt.Errorf("%w: give either query or job_id, not both", ErrInvalidSearch)
+	}
+
+	job, err := s.jobRepo.FindByID(ctx, req.JobID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return searchText{}, ErrJobNotFound
+	}
+	if err != nil {
+		return searchText{}, err
+	}
+
+	keywords := keywordQuery(job.Title)
+	if len(job.RequiredSkills) > 0 {
+		quoted := make([]string, len(job.RequiredSkills))
+		for i, skill := range job.RequiredSkills {
+			quoted[i] = `"` + strings.ReplaceAll(skill, `"`, "") + `"`
+		}
+		keywords = strings.Join(quoted, " or ")
+	}
+	return searchText{
+		semantic: strings.TrimSpace(job.Title + "\n" + job.Description),
+		keywords: keywords,
+	}, nil
+}
+
+// keywordQuery makes any of the words match instead of all of them, keeping
+// quoted phrases together, so CVs rank by how many terms they contain.
+func keywordQuery(q string) string {
+	var terms []string
+	for i, part := range strings.Split(q, `"`) {
+		if i%2 == 1 {
+			if p := strings.TrimSpace(part); p != "" {
+				terms = append(terms, `"`+p+`"`)
+			}
+			continue
+		}
+		for _, w := range strings.Fields(part) {
+			if !strings.EqualFold(w, "or") {
+				terms = append(terms, w)
+			}
+		}
+	}
+	return strings.Join(terms, " or ")
+}
@@ -0,0 +1,170 @@
+// Package contacts finds and normalizes the contact details and profile
+// links in a CV.
+package contacts
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/GazDuckington/go-gin/pkgs/redact"
+)
+
+// Kind classifies a contact.
+type Kind string
+
+const (
+	KindEmail     Kind = "email"
+	KindPhone     Kind = "phone"
+	KindLinkedIn  Kind = "linkedin"
+	KindGitHub    Kind = "github"
+	KindGitLab    Kind = "gitlab"
+	KindPortfolio Kind = "portfolio"
+)
+
+// Where a contact was found.
+const (
+	SourceAnnotation = "annotation"
+	SourceText       = "text"
+)
+
+// Contact is one normalized contact detail.
+type Contact struct {
+	Kind   Kind   `json:"kind"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// IsLink reports whether the contact is a profile or portfolio link rather
+// than a way to reach the candidate directly.
+func (c Contact) IsLink() bool {
+	return c.Kind != KindEmail && c.Kind != KindPhone
+}
+
+// Extract collects contacts from link targets (PDF URI annotations, document
+// hyperlinks) and from the text, normalized and without duplicates.
+// Annotations win over text when both have the same value.
+func Extract(text string, links []string) []Contact {
+	seen := map[string]bool{}
+	var out []Contact
+	add := func(c Contact, ok bool) {
+		key := string(c.Kind) + "|" + c.Value
+		if !ok || seen[key] {
+			return
+		}
+		seen[key] = true
+		out = append(out, c)
+	}
+
+	for _, link := range links {
+		add(FromLink(link, SourceAnnotation))
+	}
+
+	// links first, so the digits and addresses inside them are not picked up again
+	rest := redact.URLPattern.ReplaceAllStringFunc(text, func(s string) string {
+		add(FromLink(s, SourceText))
+		return " "
+	})
+	for _, s := range redact.EmailPattern.FindAllString(rest, -1) {
+		add(Contact{Kind: KindEmail, Value: NormalizeEmail(s), Source: SourceText}, true)
+	}
+	rest = redact.EmailPattern.ReplaceAllString(rest, " ")
+	for _, s := range redact.PhonePattern.FindAllString(rest, -1) {
+		phone, ok := NormalizePhone(s)
+		add(Contact{Kind: KindPhone, Value: phone, Source: SourceText}, ok)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Kind < out[j].Kind })
+	return out
+}
+
+// FromLink classifies a link target. mailto: and tel: links become email
+// and phone contacts; anything that isn't a web URL is dropped.
+func FromLink(link, source string) (Contact, bool) {
+	link = strings.TrimSpace(link)
+	lower := strings.ToLower(link)
+	switch {
+	case strings.HasPrefix(lower, "mailto:"):
+		addr := link[len("mailto:"):]
+		if i := strings.Index(addr, "?"); i >= 0 {
+			addr = addr[:i]
+		}
+		if !redact.EmailPattern.MatchString(addr) {
+			return Contact{}, false
+		}
+		return Contact{Kind: KindEmail, Value: NormalizeEmail(addr), Source: source}, true
+	case strings.HasPrefix(lower, "tel:"):
+		phone, ok := NormalizePhone(link[len("tel:"):])
+		return Contact{Kind: KindPhone, Value: phone, Source: source}, ok
+	}
+
+	u, ok := NormalizeURL(link)
+	if !ok {
+		return Contact{}, false
+	}
+	return Contact{Kind: classify(u), Value: u, Source: source}, true
+}
+
+func classify(u string) Kind {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return KindPortfolio
+	}
+	host := strings.TrimPrefix(parsed.Hostname(), "www.")
+	switch {
+	case host == "linkedin.com" || strings.HasSuffix(host, ".linkedin.com"):
+		return KindLinkedIn
+	case host == "github.com":
+		return KindGitHub
+	case host == "gitlab.com":
+		return KindGitLab
+	}
+	return KindPortfolio
+}
+
+// NormalizeURL adds a missing scheme, lowercases the host and drops
+// trailing punctuation, fragments and slashes.
+func NormalizeURL(raw string) (string, bool) {
+	raw = strings.TrimRight(strings.TrimSpace(raw), ".,;:!?)]}")
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !strings.Contains(u.Host, ".") {
+		return "", false
+	}
+	u.Scheme = "https"
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.Path = strings.TrimRight(u.Path, "/")
+	return u.String(), true
+}
+
+// NormalizeEmail lowercases an address.
+func NormalizeEmail(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// NormalizePhone keeps a leading + and the digits, turning a 00 prefix into
+// +. Runs that do not look like a phone number (see redact.LooksLikePhone)
+// are rejected.
+func NormalizePhone(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if !redact.LooksLikePhone(s) {
+		return "", false
+	}
+	var b strings.Builder
+	if strings.HasPrefix(s, "+") {
+		b.WriteByte('+')
+	}
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	phone := b.String()
+	if strings.HasPrefix(phone, "00") {
+		phone = "+" + phone[2:]
+	}
+	return phone, true
+}
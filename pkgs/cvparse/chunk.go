@@ -0,0 +1,94 @@
+package cvparse
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Chunk is a piece of CV text small enough to embed on its own.
+type Chunk struct {
+	Section SectionKind
+	Text    string
+}
+
+// Chunks splits text into its sections, and sections longer than maxChars
+// into pieces at line breaks, or at spaces for overlong lines. Every piece
+// starts with its section heading to keep its context. The header, which is
+// mostly name and contacts, is left out unless there is nothing else; a
+// maxChars of zero keeps sections whole.
+func Chunks(text string, maxChars int) []Chunk {
+	sections := Split(text)
+	if len(sections) > 1 && sections[0].Kind == SectionHeader {
+		sections = sections[1:]
+	}
+
+	var chunks []Chunk
+	for _, s := range sections {
+		if s.Content == "" {
+			continue
+		}
+		prefix := ""
+		if s.Heading != "" {
+			prefix = s.Heading + "\n"
+		}
+		limit := maxChars - utf8.RuneCountInString(prefix)
+		if maxChars <= 0 || utf8.RuneCountInString(s.Content) <= limit {
+			chunks = append(chunks, Chunk{Section: s.Kind, Text: prefix + s.Content})
+			continue
+		}
+		for _, piece := range splitText(s.Content, max(limit, maxChars/2)) {
+			chunks = append(chunks, Chunk{Section: s.Kind, Text: prefix + piece})
+		}
+	}
+	return chunks
+}
+
+// splitText packs lines into pieces of at most limit characters, splitting
+// overlong lines at spaces and overlong words anywhere.
+func splitText(text string, limit int) []string {
+	var units []string
+	for _, line := range strings.Split(text, "\n") {
+		if utf8.RuneCountInString(line) <= limit {
+			units = append(units, line)
+			continue
+		}
+		var words []string
+		for _, w := range strings.Fields(line) {
+			for utf8.RuneCountInString(w) > limit {
+				cut := []rune(w)
+				words = append(words, string(cut[:limit]))
+				w = string(cut[limit:])
+			}
+			words = append(words, w)
+		}
+		units = append(units, pack(words, " ", limit)...)
+	}
+	return pack(units, "\n", limit)
+}
+
+// pack joins consecutive parts with sep while they fit in limit characters.
+func pack(parts []string, sep string, limit int) []string {
+	var out []string
+	var cur strings.Builder
+	size := 0
+	for _, p := range parts {
+		n := utf8.RuneCountInString(p)
+		if size > 0 && size+len(sep)+n > limit {
+			if s := strings.TrimSpace(cur.String()); s != "" {
+				out = append(out, s)
+			}
+			cur.Reset()
+			size = 0
+		}
+		if size > 0 {
+			cur.WriteString(sep)
+			size += len(sep)
+		}
+		cur.WriteString(p)
+		size += n
+	}
+	if s := strings.TrimSpace(cur.String()); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,168 @@
+package cvparse
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateRange is the period of an experience or education entry. A zero Start
+// or End is unknown; Current means the entry is ongoing and End is unset.
+// Dates with only a year start in January and end in December.
+type DateRange struct {
+	Start   time.Time
+	End     time.Time
+	Current bool
+}
+
+// IsZero reports whether no date was found.
+func (d DateRange) IsZero() bool {
+	return d.Start.IsZero() && d.End.IsZero() && !d.Current
+}
+
+// months maps English and Indonesian month names and abbreviations to their
+// number.
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January, "januari": time.January,
+	"feb": time.February, "february": time.February, "februari": time.February, "peb": time.February,
+	"mar": time.March, "march": time.March, "maret": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May, "mei": time.May,
+	"jun": time.June, "june": time.June, "juni": time.June,
+	"jul": time.July, "july": time.July, "juli": time.July,
+	"aug": time.August, "august": time.August, "agu": time.August, "agt": time.August,
+	"ags": time.August, "agus": time.August, "agustus": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October, "okt": time.October, "oktober": time.October,
+	"nov": time.November, "november": time.November, "nop": time.November, "nopember": time.November,
+	"dec": time.December, "december": time.December, "des": time.December, "desember": time.December,
+}
+
+// words for an ongoing end date and for range separators, English and Indonesian
+var (
+	currentWords = `present|current|currently|now|today|ongoing|sekarang|saat ini|kini|sampai sekarang`
+	rangeWords   = `-|–|—|~|to|until|till|s/d|s\.d\.?|sd|sampai|hingga`
+)
+
+var (
+	monthPattern = `(?:` + monthAlternation() + `)\.?`
+	datePattern  = `(?:` + monthPattern + `\s*'?\d{4}|` + monthPattern + `\s*'\d{2}|\d{1,2}[/.]\d{4}|\d{4}[-/.]\d{1,2}(?:\b|$)|\d{4})`
+	rangePattern = regexp.MustCompile(`(?i)\b(` + datePattern + `)\s*(?:` + rangeWords + `)\s*(` + datePattern + `|` + currentWords + `)(?:\b|$)`)
+	sincePattern = regexp.MustCompile(`(?i)\b(?:since|sejak)\s+(` + datePattern + `)`)
+	yearPattern  = regexp.MustCompile(`\b(19[5-9]\d|20\d\d)\b`)
+	monthYear    = regexp.MustCompile(`(?i)^(` + monthPattern + `)\s*(?:(\d{4})|'?(\d{2}))$`)
+	numericMonth = regexp.MustCompile(`^(\d{1,2})[/.](\d{4})$`)
+	isoMonth     = regexp.MustCompile(`^(\d{4})[-/.](\d{1,2})$`)
+	currentWord  = regexp.MustCompile(`(?i)^(?:` + currentWords + `)$`)
+)
+
+// monthAlternation lists month names longest first, so "june" wins over "jun".
+func monthAlternation() string {
+	names := make([]string, 0, len(months))
+	for name := range months {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return strings.Join(names, "|")
+}
+
+// FindDateRange finds the first date range in s, such as "Jan 2020 - Present",
+// "2017 – 2021", "03/2019 s/d 06/2021" or "sejak Maret 2022", and returns it
+// with its position in s. Two-digit years ("Jan '20") are read as the latest
+// year not past the one after asOf.
+func FindDateRange(s string, asOf time.Time) (DateRange, []int, bool) {
+	if m := rangePattern.FindStringSubmatchIndex(s); m != nil {
+		start, ok := parseDate(s[m[2]:m[3]], false, asOf)
+		if !ok {
+			return DateRange{}, nil, false
+		}
+		d := DateRange{Start: start}
+
+		endText := s[m[4]:m[5]]
+		if currentWord.MatchString(endText) {
+			d.Current = true
+		} else if end, ok := parseDate(endText, true, asOf); ok && !end.Before(start) {
+			d.End = end
+		} else {
+			return DateRange{}, nil, false
+		}
+		return d, []int{m[0], m[1]}, true
+	}
+
+	if m := sincePattern.FindStringSubmatchIndex(s); m != nil {
+		if start, ok := parseDate(s[m[2]:m[3]], false, asOf); ok {
+			return DateRange{Start: start, Current: true}, []int{m[0], m[1]}, true
+		}
+	}
+	return DateRange{}, nil, false
+}
+
+// FindYear finds a lone year, as in "Universitas Indonesia, 2017", and
+// returns it as a range ending that year.
+func FindYear(s string) (DateRange, []int, bool) {
+	m := yearPattern.FindStringIndex(s)
+	if m == nil {
+		return DateRange{}, nil, false
+	}
+	year, _ := strconv.Atoi(s[m[0]:m[1]])
+	return DateRange{End: time.Date(year, time.December, 1, 0, 0, 0, 0, time.UTC)}, m, true
+}
+
+// parseDate reads one date; end picks December for a bare year.
+func parseDate(s string, end bool, asOf time.Time) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	var year int
+	month := time.January
+	if end {
+		month = time.December
+	}
+
+	switch {
+	case monthYear.MatchString(s):
+		m := monthYear.FindStringSubmatch(s)
+		month = months[strings.TrimSuffix(strings.ToLower(m[1]), ".")]
+		if m[2] != "" {
+			year, _ = strconv.Atoi(m[2])
+		} else {
+			// "Jan '20"
+			yy, _ := strconv.Atoi(m[3])
+			year = 2000 + yy
+			if year > asOf.Year()+1 {
+				year -= 100
+			}
+		}
+	case numericMonth.MatchString(s):
+		m := numericMonth.FindStringSubmatch(s)
+		n, _ := strconv.Atoi(m[1])
+		if n < 1 || n > 12 {
+			return time.Time{}, false
+		}
+		month = time.Month(n)
+		year, _ = strconv.Atoi(m[2])
+	case isoMonth.MatchString(s):
+		m := isoMonth.FindStringSubmatch(s)
+		n, _ := strconv.Atoi(m[2])
+		if n < 1 || n > 12 {
+			return time.Time{}, false
+		}
+		month = time.Month(n)
+		year, _ = strconv.Atoi(m[1])
+	default:
+		var err error
+		if year, err = strconv.Atoi(s); err != nil {
+			return time.Time{}, false
+		}
+	}
+
+	if year < 1950 || year > 2100 {
+		return time.Time{}, false
+	}
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), true
+}
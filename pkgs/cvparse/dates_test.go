@@ -0,0 +1,103 @@
+package cvparse
+
+import (
+	"testing"
+	"time"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestFindDateRange(t *testing.T) {
+	asOf := time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want DateRange
+		ok   bool
+	}{
+		{in: "Backend Engineer, Jan 2020 – Present", want: DateRange{Start: month(2020, time.January), Current: true}, ok: true},
+		{in: "2019-2021", want: DateRange{Start: month(2019, time.January), End: month(2021, time.December)}, ok: true},
+		{in: "2017 — 2021", want: DateRange{Start: month(2017, time.January), End: month(2021, time.December)}, ok: true},
+		{in: "March 2018 to June 2019", want: DateRange{Start: month(2018, time.March), End: month(2019, time.June)}, ok: true},
+		{in: "Sept. 2015 until Aug 2016", want: DateRange{Start: month(2015, time.September), End: month(2016, time.August)}, ok: true},
+		{in: "Jan '20 - Dec '21", want: DateRange{Start: month(2020, time.January), End: month(2021, time.December)}, ok: true},
+		{in: "03/2019 s/d 06/2021", want: DateRange{Start: month(2019, time.March), End: month(2021, time.June)}, ok: true},
+		{in: "2020-05 to 2022-11", want: DateRange{Start: month(2020, time.May), End: month(2022, time.November)}, ok: true},
+		{in: "Agustus 2019 - sekarang", want: DateRange{Start: month(2019, time.August), Current: true}, ok: true},
+		{in: "Mei 2016 sampai Desember 2018", want: DateRange{Start: month(2016, time.May), End: month(2018, time.December)}, ok: true},
+		{in: "Nopember 2020 hingga saat ini", want: DateRange{Start: month(2020, time.November), Current: true}, ok: true},
+		{in: "sejak Maret 2022", want: DateRange{Start: month(2022, time.March), Current: true}, ok: true},
+		{in: "since Oct 2021", want: DateRange{Start: month(2021, time.October), Current: true}, ok: true},
+		{in: "2021 - 2019", ok: false},
+		{in: "13/2019 - 06/2021", ok: false},
+		{in: "1800 - 1820", ok: false},
+		{in: "Go, SQL, Docker", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, _, ok := FindDateRange(tt.in, asOf)
+			if ok != tt.ok {
+				t.Fatalf("FindDateRange(%q) ok = %v, want %v", tt.in, ok, tt.ok)
+			}
+			if got != tt.want {
+				t.Errorf("FindDateRange(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDateRangePosition(t *testing.T) {
+	in := "Acme Corp | Jan 2020 - Present"
+	_, pos, ok := FindDateRange(in, time.Now())
+	if !ok {
+		t.Fatal("no range found")
+	}
+	if got := in[pos[0]:pos[1]]; got != "Jan 2020 - Present" {
+		t.Errorf("matched %q", got)
+	}
+}
+
+func TestFindDateRangeTwoDigitYears(t *testing.T) {
+	tests := []struct {
+		in   string
+		asOf int
+		want DateRange
+		ok   bool
+	}{
+		{in: "Jan '20 - Present", asOf: 2024, want: DateRange{Start: month(2020, time.January), Current: true}, ok: true},
+		{in: "Jan '25 - Present", asOf: 2024, want: DateRange{Start: month(2025, time.January), Current: true}, ok: true},
+		{in: "Jan '98 - Dec '99", asOf: 2024, want: DateRange{Start: month(1998, time.January), End: month(1999, time.December)}, ok: true},
+		{in: "Jan '25 - Present", asOf: 2020, ok: false}, // 1925
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			asOf := time.Date(tt.asOf, time.June, 1, 0, 0, 0, 0, time.UTC)
+			got, _, ok := FindDateRange(tt.in, asOf)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("FindDateRange(%q) as of %d = %+v, %v, want %+v, %v", tt.in, tt.asOf, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFindYear(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+		ok   bool
+	}{
+		{in: "Universitas Indonesia, 2017", want: month(2017, time.December), ok: true},
+		{in: "Class of 1998", want: month(1998, time.December), ok: true},
+		{in: "Room 1234", ok: false},
+		{in: "no year", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, _, ok := FindYear(tt.in)
+			if ok != tt.ok || got.End != tt.want {
+				t.Errorf("FindYear(%q) = %v, %v, want %v, %v", tt.in, got.End, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
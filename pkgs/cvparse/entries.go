@@ -0,0 +1,224 @@
+package cvparse
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Experience is one job.
+type Experience struct {
+	Role        string
+	Employer    string
+	Dates       DateRange
+	Description string
+}
+
+// Education is one degree or school.
+type Education struct {
+	Institution string
+	Degree      string
+	Dates       DateRange
+}
+
+// Parsed is a CV split into sections with typed entries.
+type Parsed struct {
+	Sections    []Section
+	Experiences []Experience
+	Educations  []Education
+}
+
+// Parse splits text into sections and extracts the experience and education
+// entries, reading two-digit years as of asOf.
+func Parse(text string, asOf time.Time) Parsed {
+	p := Parsed{Sections: Split(text)}
+	for _, s := range p.Sections {
+		switch s.Kind {
+		case SectionExperience:
+			p.Experiences = append(p.Experiences, parseExperiences(s.Content, asOf)...)
+		case SectionEducation:
+			p.Educations = append(p.Educations, parseEducations(s.Content, asOf)...)
+		}
+	}
+	return p
+}
+
+// entry is a run of header lines (role, employer, dates) and the description
+// lines under them.
+type entry struct {
+	header []string
+	body   []string
+}
+
+var bulletPrefix = regexp.MustCompile(`^\s*(?:[-*•●▪■◦‣∙·–]|\d+[.)])\s+`)
+
+// splitEntries groups section lines into entries: a new entry starts at a
+// header line following description lines, or at a header line with dates
+// when the current entry already has dates.
+func splitEntries(content string, asOf time.Time) []entry {
+	var entries []entry
+	var cur *entry
+	hasDates := false
+	for _, line := range strings.Split(content, "\n") {
+		line = tableRow(line)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		_, _, dated := FindDateRange(line, asOf)
+
+		if isDescription(line) {
+			if cur == nil {
+				entries = append(entries, entry{})
+				cur = &entries[len(entries)-1]
+			}
+			cur.body = append(cur.body, strings.TrimSpace(bulletPrefix.ReplaceAllString(line, "")))
+			continue
+		}
+
+		if cur == nil || len(cur.body) > 0 || (dated && hasDates) || len(cur.header) >= 3 {
+			entries = append(entries, entry{})
+			cur = &entries[len(entries)-1]
+			hasDates = false
+		}
+		cur.header = append(cur.header, strings.TrimSpace(line))
+		hasDates = hasDates || dated
+	}
+	return entries
+}
+
+// isDescription tells bullets and running sentences from header lines.
+func isDescription(line string) bool {
+	return bulletPrefix.MatchString(line) || len(strings.Fields(line)) > 12
+}
+
+// tableRow turns a Markdown table row into "a | b | c"; separator rows
+// become empty.
+func tableRow(line string) string {
+	t := strings.TrimSpace(line)
+	if !strings.HasPrefix(t, "|") {
+		return line
+	}
+	t = strings.Trim(t, "|")
+	if strings.Trim(t, "|-: ") == "" {
+		return ""
+	}
+	cells := strings.Split(t, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return strings.Join(cells, " | ")
+}
+
+// takeDates removes the first date range, or failing that a lone year, from
+// the header lines.
+func takeDates(header []string, allowYear bool, asOf time.Time) (DateRange, []string) {
+	for i, line := range header {
+		if d, loc, ok := FindDateRange(line, asOf); ok {
+			header[i] = line[:loc[0]] + line[loc[1]:]
+			return d, header
+		}
+	}
+	if allowYear {
+		for i, line := range header {
+			if d, loc, ok := FindYear(line); ok {
+				header[i] = line[:loc[0]] + line[loc[1]:]
+				return d, header
+			}
+		}
+	}
+	return DateRange{}, header
+}
+
+// fieldSeparator splits "Role at Employer", "Role, Employer", "Role | Employer"
+// and dashes used the same way.
+var fieldSeparator = regexp.MustCompile(`\s+(?:at|@|di)\s+|\s*[,|]\s*|\s+[-–—]\s+`)
+
+// fields splits header lines into their non-empty parts.
+func fields(header []string) []string {
+	var out []string
+	for _, line := range header {
+		for _, f := range fieldSeparator.Split(line, -1) {
+			f = strings.Trim(f, " \t-–—,|()")
+			if f != "" {
+				out = append(out, f)
+			}
+		}
+	}
+	return out
+}
+
+var roleWords = regexp.MustCompile(`(?i)\b(?:engineer|developer|programmer|manager|intern|internship|analyst|designer|lead|consultant|architect|scientist|specialist|officer|staff|administrator|admin|head|director|tester|qa|devops|sre|founder|owner|coordinator|assistant|associate|executive|supervisor|technician|researcher|cto|ceo|vp|magang|karyawan)\b`)
+
+func parseExperiences(content string, asOf time.Time) []Experience {
+	var out []Experience
+	for _, e := range splitEntries(content, asOf) {
+		if len(e.header) == 0 {
+			continue
+		}
+		dates, header := takeDates(e.header, false, asOf)
+		parts := fields(header)
+		if len(parts) == 0 && dates.IsZero() {
+			continue
+		}
+
+		exp := Experience{Dates: dates, Description: strings.Join(e.body, "\n")}
+		// the role is the part that reads like a job title; the employer
+		// is the next part, or the one before it
+		role := 0
+		for i, p := range parts {
+			if roleWords.MatchString(p) {
+				role = i
+				break
+			}
+		}
+		if len(parts) > 0 {
+			exp.Role = parts[role]
+		}
+		switch {
+		case role+1 < len(parts):
+			exp.Employer = parts[role+1]
+		case role > 0:
+			exp.Employer = parts[role-1]
+		}
+		out = append(out, exp)
+	}
+	return out
+}
+
+var (
+	institutionWords = regexp.MustCompile(`(?i)\b(?:university|universitas|institut|institute|college|school|sekolah|politeknik|polytechnic|academy|akademi|sma|smk|stmik|stie)\b`)
+	degreeWords      = regexp.MustCompile(`(?i)\b(?:bachelor|master|doctor|phd|ph\.d|diploma|sarjana|magister|degree|associate|b\.?sc|m\.?sc|b\.?eng|m\.?eng|b\.?a|m\.?a|b\.?s|m\.?s|mba|s1|s2|s3|d3|d4)\b`)
+)
+
+func parseEducations(content string, asOf time.Time) []Education {
+	var out []Education
+	for _, e := range splitEntries(content, asOf) {
+		if len(e.header) == 0 {
+			continue
+		}
+		dates, header := takeDates(e.header, true, asOf)
+		parts := fields(header)
+		if len(parts) == 0 {
+			continue
+		}
+
+		edu := Education{Dates: dates}
+		var rest []string
+		for _, p := range parts {
+			switch {
+			case edu.Institution == "" && institutionWords.MatchString(p):
+				edu.Institution = p
+			case edu.Degree == "" && degreeWords.MatchString(p):
+				edu.Degree = p
+			default:
+				rest = append(rest, p)
+			}
+		}
+		// "B.Sc. Computer Science" may have been split from its field
+		if edu.Degree == "" && len(rest) > 0 {
+			edu.Degree = rest[0]
+		}
+		out = append(out, edu)
+	}
+	return out
+}
@@ -0,0 +1,196 @@
+package cvparse
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Period is a span of months; End is exclusive (the first month after it).
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Months is the length of p in whole months.
+func (p Period) Months() int {
+	return (p.End.Year()-p.Start.Year())*12 + int(p.End.Month()-p.Start.Month())
+}
+
+// MergePeriods turns date ranges into non-overlapping periods, counting both
+// the start and the end month. Ongoing ranges end at asOf; ranges without a
+// start are skipped, as are dates after asOf.
+func MergePeriods(ranges []DateRange, asOf time.Time) []Period {
+	limit := monthStart(asOf).AddDate(0, 1, 0)
+
+	var periods []Period
+	for _, d := range ranges {
+		if d.Start.IsZero() || (d.End.IsZero() && !d.Current) {
+			continue
+		}
+		end := limit
+		if !d.Current {
+			end = d.End.AddDate(0, 1, 0)
+		}
+		if end.After(limit) {
+			end = limit
+		}
+		if d.Start.Before(end) {
+			periods = append(periods, Period{Start: d.Start, End: end})
+		}
+	}
+
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+	var merged []Period
+	for _, p := range periods {
+		if n := len(merged); n > 0 && !p.Start.After(merged[n-1].End) {
+			if p.End.After(merged[n-1].End) {
+				merged[n-1].End = p.End
+			}
+			continue
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ExperienceYears is the experience computed from employment dates, with
+// overlapping jobs counted once.
+type ExperienceYears struct {
+	TotalMonths    int     `json:"total_months"`
+	RelevantMonths int     `json:"relevant_months"`
+	TotalYears     float64 `json:"total_years"`
+	RelevantYears  float64 `json:"relevant_years"`
+}
+
+// ComputeExperience totals the experience entries as of asOf. Relevant
+// experience only counts roles that share a meaningful word with target, the
+// job title applied for; with no target every role is relevant.
+func ComputeExperience(exps []Experience, target string, asOf time.Time) ExperienceYears {
+	var all, relevant []DateRange
+	isRelevant := RelevantTo(target)
+	for _, e := range exps {
+		all = append(all, e.Dates)
+		if isRelevant(e) {
+			relevant = append(relevant, e.Dates)
+		}
+	}
+
+	y := ExperienceYears{
+		TotalMonths:    sumMonths(MergePeriods(all, asOf)),
+		RelevantMonths: sumMonths(MergePeriods(relevant, asOf)),
+	}
+	y.TotalYears = toYears(y.TotalMonths)
+	y.RelevantYears = toYears(y.RelevantMonths)
+	return y
+}
+
+func sumMonths(periods []Period) int {
+	total := 0
+	for _, p := range periods {
+		total += p.Months()
+	}
+	return total
+}
+
+func toYears(months int) float64 {
+	return math.Round(float64(months)/12*10) / 10
+}
+
+var titleWord = regexp.MustCompile(`[\p{L}\p{N}+#.]+`)
+
+// genericTitleWords say nothing about the field of a role.
+var genericTitleWords = map[string]bool{
+	"senior": true, "sr": true, "sr.": true, "junior": true, "jr": true, "jr.": true,
+	"lead": true, "principal": true, "staff": true, "head": true, "chief": true,
+	"intern": true, "internship": true, "trainee": true, "associate": true, "assistant": true,
+	"i": true, "ii": true, "iii": true, "iv": true, "of": true, "and": true, "&": true,
+	"engineer": true, "developer": true, "specialist": true, "officer": true, "manager": true,
+	"consultant": true, "analyst": true, "freelance": true, "part-time": true, "contract": true,
+}
+
+// RelevantTo matches roles against a target job title by their non-generic
+// words ("Backend" in "Senior Backend Engineer"). When the target only has
+// generic words, any shared word counts.
+func RelevantTo(target string) func(Experience) bool {
+	specific, generic := titleWords(target)
+	if len(specific) == 0 && len(generic) == 0 {
+		return func(Experience) bool { return true }
+	}
+	return func(e Experience) bool {
+		roleSpecific, roleGeneric := titleWords(e.Role)
+		if len(specific) > 0 {
+			return overlaps(specific, roleSpecific)
+		}
+		return overlaps(generic, roleGeneric)
+	}
+}
+
+func titleWords(title string) (specific, generic map[string]bool) {
+	specific, generic = map[string]bool{}, map[string]bool{}
+	for _, w := range titleWord.FindAllString(strings.ToLower(title), -1) {
+		if genericTitleWords[w] {
+			generic[w] = true
+		} else {
+			specific[w] = true
+		}
+	}
+	return specific, generic
+}
+
+func overlaps(a, b map[string]bool) bool {
+	for w := range a {
+		if b[w] {
+			return true
+		}
+	}
+	return false
+}
+
+// ExperienceScore maps years to the Experience Level rubric scale:
+// 1=<1yr, 2=1–2yrs, 3=2–3yrs, 4=3–4yrs, 5=5+yrs (4 up to 5 years).
+func ExperienceScore(years float64) int {
+	switch {
+	case years < 1:
+		return 1
+	case years < 2:
+		return 2
+	case years < 3:
+		return 3
+	case years < 5:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// ExperienceCheck compares the LLM's Experience Level score with the score
+// the computed years map to.
+type ExperienceCheck struct {
+	ExperienceYears
+	ExpectedScore int     `json:"expected_score"`
+	LLMScore      float64 `json:"llm_score"`
+	Consistent    bool    `json:"consistent"`
+}
+
+// CheckExperienceScore scores relevant experience, or total experience when
+// no role matched the target, and accepts an LLM score within one point.
+func CheckExperienceScore(years ExperienceYears, llmScore float64) ExperienceCheck {
+	basis := years.RelevantYears
+	if years.RelevantMonths == 0 {
+		basis = years.TotalYears
+	}
+	expected := ExperienceScore(basis)
+	return ExperienceCheck{
+		ExperienceYears: years,
+		ExpectedScore:   expected,
+		LLMScore:        llmScore,
+		Consistent:      math.Abs(llmScore-float64(expected)) <= 1,
+	}
+}
@@ -0,0 +1,158 @@
+package cvparse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergePeriods(t *testing.T) {
+	asOf := time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		ranges []DateRange
+		months int
+		count  int
+	}{
+		{
+			name:   "start and end month both count",
+			ranges: []DateRange{{Start: month(2020, time.January), End: month(2020, time.December)}},
+			months: 12,
+			count:  1,
+		},
+		{
+			name: "overlapping jobs count once",
+			ranges: []DateRange{
+				{Start: month(2018, time.January), End: month(2020, time.December)},
+				{Start: month(2020, time.January), End: month(2021, time.June)},
+			},
+			months: 42,
+			count:  1,
+		},
+		{
+			name: "adjacent jobs merge",
+			ranges: []DateRange{
+				{Start: month(2019, time.January), End: month(2019, time.June)},
+				{Start: month(2019, time.July), End: month(2019, time.December)},
+			},
+			months: 12,
+			count:  1,
+		},
+		{
+			name: "gaps are kept",
+			ranges: []DateRange{
+				{Start: month(2016, time.January), End: month(2016, time.December)},
+				{Start: month(2018, time.January), End: month(2018, time.June)},
+			},
+			months: 18,
+			count:  2,
+		},
+		{
+			name: "contained job adds nothing",
+			ranges: []DateRange{
+				{Start: month(2015, time.January), End: month(2019, time.December)},
+				{Start: month(2016, time.March), End: month(2017, time.March)},
+			},
+			months: 60,
+			count:  1,
+		},
+		{
+			name:   "ongoing ends at asOf",
+			ranges: []DateRange{{Start: month(2023, time.July), Current: true}},
+			months: 12,
+			count:  1,
+		},
+		{
+			name:   "future end is capped",
+			ranges: []DateRange{{Start: month(2024, time.January), End: month(2025, time.December)}},
+			months: 6,
+			count:  1,
+		},
+		{
+			name: "unknown start or end is skipped",
+			ranges: []DateRange{
+				{End: month(2020, time.December)},
+				{Start: month(2020, time.January)},
+			},
+		},
+		{
+			name:   "starts after asOf",
+			ranges: []DateRange{{Start: month(2025, time.January), Current: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periods := MergePeriods(tt.ranges, asOf)
+			if len(periods) != tt.count || sumMonths(periods) != tt.months {
+				t.Errorf("MergePeriods = %d periods, %d months, want %d, %d", len(periods), sumMonths(periods), tt.count, tt.months)
+			}
+		})
+	}
+}
+
+func TestComputeExperience(t *testing.T) {
+	asOf := time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)
+	exps := []Experience{
+		{Role: "Senior Backend Engineer", Dates: DateRange{Start: month(2021, time.January), Current: true}},
+		{Role: "Backend Developer", Dates: DateRange{Start: month(2019, time.January), End: month(2021, time.June)}},
+		{Role: "Frontend Developer", Dates: DateRange{Start: month(2017, time.January), End: month(2018, time.December)}},
+	}
+	tests := []struct {
+		target string
+		want   ExperienceYears
+	}{
+		{target: "Backend Engineer", want: ExperienceYears{TotalMonths: 96, RelevantMonths: 72, TotalYears: 8, RelevantYears: 6}},
+		{target: "Frontend Engineer", want: ExperienceYears{TotalMonths: 96, RelevantMonths: 24, TotalYears: 8, RelevantYears: 2}},
+		{target: "Data Scientist", want: ExperienceYears{TotalMonths: 96, TotalYears: 8}},
+		{target: "", want: ExperienceYears{TotalMonths: 96, RelevantMonths: 96, TotalYears: 8, RelevantYears: 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if got := ComputeExperience(exps, tt.target, asOf); got != tt.want {
+				t.Errorf("ComputeExperience(%q) = %+v, want %+v", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelevantTo(t *testing.T) {
+	tests := []struct {
+		target, role string
+		want         bool
+	}{
+		{target: "Senior Backend Engineer", role: "Backend Developer", want: true},
+		{target: "Senior Backend Engineer", role: "Senior Frontend Engineer", want: false},
+		{target: "Engineer", role: "Senior Engineer", want: true},
+		{target: "Engineer", role: "Product Manager", want: false},
+		{target: "Node.js Developer", role: "Node.js Engineer", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+"/"+tt.role, func(t *testing.T) {
+			if got := RelevantTo(tt.target)(Experience{Role: tt.role}); got != tt.want {
+				t.Errorf("RelevantTo(%q)(%q) = %v, want %v", tt.target, tt.role, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckExperienceScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		years      ExperienceYears
+		llm        float64
+		expected   int
+		consistent bool
+	}{
+		{name: "relevant years", years: ExperienceYears{RelevantMonths: 30, RelevantYears: 2.5, TotalYears: 6}, llm: 3, expected: 3, consistent: true},
+		{name: "falls back to total", years: ExperienceYears{TotalMonths: 72, TotalYears: 6}, llm: 4, expected: 5, consistent: true},
+		{name: "inflated", years: ExperienceYears{RelevantMonths: 6, RelevantYears: 0.5}, llm: 5, expected: 1, consistent: false},
+		{name: "under a year", years: ExperienceYears{RelevantMonths: 11, RelevantYears: 0.9}, llm: 2, expected: 1, consistent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckExperienceScore(tt.years, tt.llm)
+			if got.ExpectedScore != tt.expected || got.Consistent != tt.consistent {
+				t.Errorf("CheckExperienceScore = expected %d consistent %v, want %d %v", got.ExpectedScore, got.Consistent, tt.expected, tt.consistent)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Package cvparse splits CV text into sections and pulls typed entries, such
+// as jobs and degrees, out of them.
+package cvparse
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SectionKind classifies a CV section.
+type SectionKind string
+
+const (
+	SectionHeader         SectionKind = "header" // text before the first heading: name, contacts
+	SectionSummary        SectionKind = "summary"
+	SectionExperience     SectionKind = "experience"
+	SectionEducation      SectionKind = "education"
+	SectionSkills         SectionKind = "skills"
+	SectionProjects       SectionKind = "projects"
+	SectionCertifications SectionKind = "certifications"
+	SectionOther          SectionKind = "other"
+)
+
+// Section is a heading and the text under it.
+type Section struct {
+	Kind    SectionKind
+	Heading string
+	Content string
+}
+
+// headings maps normalized heading text, English and Indonesian, to a kind.
+var headings = map[string]SectionKind{
+	"summary": SectionSummary, "professional summary": SectionSummary, "profile": SectionSummary,
+	"professional profile": SectionSummary, "about": SectionSummary, "about me": SectionSummary,
+	"objective": SectionSummary, "career objective": SectionSummary,
+	"ringkasan": SectionSummary, "profil": SectionSummary, "tentang saya": SectionSummary,
+
+	"experience": SectionExperience, "work experience": SectionExperience,
+	"professional experience": SectionExperience, "employment": SectionExperience,
+	"employment history": SectionExperience, "work history": SectionExperience,
+	"career history": SectionExperience, "pengalaman": SectionExperience,
+	"pengalaman kerja": SectionExperience, "riwayat pekerjaan": SectionExperience,
+
+	"education": SectionEducation, "academic background": SectionEducation,
+	"educational background": SectionEducation, "pendidikan": SectionEducation,
+	"riwayat pendidikan": SectionEducation,
+
+	"skills": SectionSkills, "technical skills": SectionSkills, "core competencies": SectionSkills,
+	"competencies": SectionSkills, "tech stack": SectionSkills, "technologies": SectionSkills,
+	"keahlian": SectionSkills, "kemampuan": SectionSkills, "keterampilan": SectionSkills,
+
+	"projects": SectionProjects, "personal projects": SectionProjects, "selected projects": SectionProjects,
+	"portfolio": SectionProjects, "proyek": SectionProjects, "portofolio": SectionProjects,
+
+	"certifications": SectionCertifications, "certificates": SectionCertifications,
+	"licenses & certifications": SectionCertifications, "licenses and certifications": SectionCertifications,
+	"courses": SectionCertifications, "sertifikasi": SectionCertifications, "sertifikat": SectionCertifications,
+}
+
+// Split cuts text into sections at known headings. Unknown Markdown headings,
+// as produced by layout extraction, start an "other" section once the header
+// is over; before that they are usually the candidate's name.
+func Split(text string) []Section {
+	var sections []Section
+	cur := Section{Kind: SectionHeader}
+	var body []string
+
+	flush := func() {
+		cur.Content = strings.TrimSpace(strings.Join(body, "\n"))
+		if cur.Content != "" || cur.Kind != SectionHeader {
+			sections = append(sections, cur)
+		}
+		body = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if kind, heading, ok := headingOf(line); ok && (kind != SectionOther || len(sections) > 0 || cur.Kind != SectionHeader) {
+			flush()
+			cur = Section{Kind: kind, Heading: heading}
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return sections
+}
+
+// headingOf recognises a section heading line.
+func headingOf(line string) (SectionKind, string, bool) {
+	trimmed := strings.TrimSpace(line)
+	markdown := strings.HasPrefix(trimmed, "#")
+	heading := strings.TrimSpace(strings.Trim(trimmed, "#*_:= \t"))
+	if heading == "" || len(strings.Fields(heading)) > 4 {
+		return "", "", false
+	}
+
+	if kind, ok := headings[normalizeHeading(heading)]; ok {
+		return kind, heading, true
+	}
+	if markdown {
+		return SectionOther, heading, true
+	}
+	return "", "", false
+}
+
+func normalizeHeading(s string) string {
+	s = strings.ToLower(s)
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || r == '&' {
+			return r
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,196 @@
+// Package embedcache caches embeddings by a hash of the normalized text, the
+// model and the dimension, in memory in front of a persistent store.
+package embedcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Store persists cached embeddings, e.g. in Postgres.
+type Store interface {
+	// Get returns the embeddings stored under the keys; missing keys are
+	// left out.
+	Get(ctx context.Context, keys []string) (map[string][]float32, error)
+	// Put stores embeddings by key, keeping any already stored.
+	Put(ctx context.Context, model string, dim int, embeddings map[string][]float32) error
+	// Purge deletes the embeddings of other models or dimensions than these;
+	// with an empty model it deletes everything.
+	Purge(ctx context.Context, model string, dim int) (int64, error)
+}
+
+// EmbedFunc embeds texts, one vector per text in order.
+type EmbedFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// Normalize is the form of text the key is computed from: NFC with runs of
+// whitespace collapsed to one space, so reformatting alone does not miss.
+func Normalize(text string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(text)), " ")
+}
+
+// Key is the SHA-256 of the model, the dimension and the normalized text.
+func Key(model string, dim int, text string) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.Itoa(dim)))
+	h.Write([]byte{0})
+	h.Write([]byte(Normalize(text)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Stats are the cache's counters since start. Every text asked for counts
+// once as a memory hit, a store hit or a miss.
+type Stats struct {
+	Model       string  `json:"model"`
+	Dim         int     `json:"dim"`
+	Persistent  bool    `json:"persistent"`
+	Entries     int     `json:"entries"`
+	Capacity    int     `json:"capacity"`
+	MemoryHits  uint64  `json:"memory_hits"`
+	StoreHits   uint64  `json:"store_hits"`
+	Misses      uint64  `json:"misses"`
+	HitRate     float64 `json:"hit_rate"`
+	StoreErrors uint64  `json:"store_errors"`
+}
+
+// Cache looks embeddings up in an LRU of size entries, then in the store,
+// and only embeds what neither has. A failing store is logged and skipped,
+// so it never fails an embedding.
+type Cache struct {
+	model  string
+	dim    int
+	lru    *lru
+	store  Store // nil keeps embeddings in memory only
+	logger *logrus.Logger
+
+	memoryHits, storeHits, misses, storeErrors atomic.Uint64
+}
+
+// New returns a cache for embeddings of model with dim values.
+func New(model string, dim, size int, store Store, logger *logrus.Logger) *Cache {
+	return &Cache{
+		model:  model,
+		dim:    dim,
+		lru:    newLRU(size),
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Embed returns the embeddings of texts, calling embed once for those not
+// cached; texts that repeat are embedded once.
+func (c *Cache) Embed(ctx context.Context, texts []string, embed EmbedFunc) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	missing := map[string][]int{}
+	var order []string
+	for i, text := range texts {
+		key := Key(c.model, c.dim, text)
+		if v, ok := c.lru.get(key); ok {
+			out[i] = slices.Clone(v)
+			c.memoryHits.Add(1)
+			continue
+		}
+		if _, ok := missing[key]; !ok {
+			order = append(order, key)
+		}
+		missing[key] = append(missing[key], i)
+	}
+	if len(order) == 0 {
+		return out, nil
+	}
+
+	if c.store != nil {
+		found, err := c.store.Get(ctx, order)
+		if err != nil {
+			c.storeErrors.Add(1)
+			c.logger.Warnf("[embedcache] lookup failed: %v", err)
+		}
+		for key, v := range found {
+			if len(v) != c.dim {
+				continue
+			}
+			c.lru.add(key, v)
+			for _, i := range missing[key] {
+				out[i] = slices.Clone(v)
+				c.storeHits.Add(1)
+			}
+			delete(missing, key)
+		}
+		order = slices.DeleteFunc(order, func(key string) bool { _, ok := missing[key]; return !ok })
+		if len(order) == 0 {
+			return out, nil
+		}
+	}
+
+	batch := make([]string, len(order))
+	for j, key := range order {
+		batch[j] = texts[missing[key][0]]
+	}
+	vectors, err := embed(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := make(map[string][]float32, len(order))
+	for j, key := range order {
+		fresh[key] = vectors[j]
+		c.lru.add(key, vectors[j])
+		for _, i := range missing[key] {
+			out[i] = slices.Clone(vectors[j])
+			c.misses.Add(1)
+		}
+	}
+	if c.store != nil {
+		if err := c.store.Put(ctx, c.model, c.dim, fresh); err != nil {
+			c.storeErrors.Add(1)
+			c.logger.Warnf("[embedcache] failed to store %d embeddings: %v", len(fresh), err)
+		}
+	}
+	return out, nil
+}
+
+// Invalidate drops the embeddings of other models and dimensions from the
+// store, which can never be hit again, and returns how many were deleted.
+// With all it empties the memory and the store entirely.
+func (c *Cache) Invalidate(ctx context.Context, all bool) (int64, error) {
+	model := c.model
+	if all {
+		c.lru.clear()
+		model = ""
+	}
+	if c.store == nil {
+		return 0, nil
+	}
+	return c.store.Purge(ctx, model, c.dim)
+}
+
+// Stats returns the counters; a nil cache is disabled and has none.
+func (c *Cache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+	s := Stats{
+		Model:       c.model,
+		Dim:         c.dim,
+		Persistent:  c.store != nil,
+		Entries:     c.lru.len(),
+		Capacity:    c.lru.size,
+		MemoryHits:  c.memoryHits.Load(),
+		StoreHits:   c.storeHits.Load(),
+		Misses:      c.misses.Load(),
+		StoreErrors: c.storeErrors.Load(),
+	}
+	if total := s.MemoryHits + s.StoreHits + s.Misses; total > 0 {
+		s.HitRate = float64(s.MemoryHits+s.StoreHits) / float64(total)
+	}
+	return s
+}
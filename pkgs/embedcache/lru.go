@@ -0,0 +1,67 @@
+package embedcache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru keeps the most recently used embeddings up to size entries; a size
+// of zero keeps none.
+type lru struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // front is the most recently used
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key    string
+	vector []float32
+}
+
+func newLRU(size int) *lru {
+	return &lru{size: max(size, 0), order: list.New(), items: map[string]*list.Element{}}
+}
+
+func (l *lru) get(key string) ([]float32, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(e)
+	return e.Value.(*lruEntry).vector, true
+}
+
+func (l *lru) add(key string, vector []float32) {
+	if l.size == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e, ok := l.items[key]; ok {
+		e.Value.(*lruEntry).vector = vector
+		l.order.MoveToFront(e)
+		return
+	}
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, vector: vector})
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (l *lru) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *lru) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.order.Init()
+	clear(l.items)
+}
@@ -9,11 +9,19 @@ import (
 	"github.com/GazDuckington/go-gin/internal/config"
 	"github.com/GazDuckington/go-gin/internal/models/dto"
 	"github.com/GazDuckington/go-gin/internal/models/entity"
+	"github.com/GazDuckington/go-gin/pkgs/embedcache"
 	"google.golang.org/genai"
 )
 
 var GemniClient *genai.Client
 
+// EmbeddingModel and EmbeddingDim are what GenerateEmbedding uses, set from
+// EMBEDDING_MODEL and EMBEDDING_DIM by Init.
+var (
+	EmbeddingModel = "text-embedding-004"
+	EmbeddingDim   = 768
+)
+
 func Init(ctx context.Context, cfg *config.Config) error {
 	client, err := genai.NewClient(ctx, &genai.ClientConfig{
 		APIKey:  cfg.GeminiKey,
@@ -24,21 +32,30 @@ func Init(ctx context.Context, cfg *config.Config) error {
 	}
 
 	GemniClient = client
+	EmbeddingModel, EmbeddingDim = cfg.EmbeddingModel, cfg.EmbeddingDim
 	return nil
 }
 
+// PromptVersion identifies the rubric prompt below; bump it whenever the
+// prompt or rubric changes so stored evaluations can be compared per version.
+const PromptVersion = "4"
+
+// EvalModel is the model used for rubric evaluations.
+const EvalModel = "gemini-2.0-flash"
+
 func buildRubricPrompt(r entity.EvaluationRubrics, cv *dto.CVResponse) string {
 	var sb strings.Builder
 
 	sb.WriteString("You are a senior technical recruiter.\n")
 	sb.WriteString("Evaluate the candidate CV based on the following rubrics and return JSON ONLY in this format:\n")
 	sb.WriteString(`{
-  "cv_scores": {...},
-  "project_scores": {...},
-  "weighted_cv_score": float between 0 and 1,
-  "weighted_project_score": float between 0 and 5,
-  "overall_summary": string,
-  "feedback": string
+  "cv_scores": {"<cv rubric name>": integer 1-5, ...},
+  "project_scores": {"<project rubric name>": integer 1-5, ...},
+  "cv_match_rate": weighted cv score as a float between 0 and 1,
+  "cv_feedback": string,
+  "project_score": weighted project score as a float between 1 and 5,
+  "project_feedback": string,
+  "overall_summary": string
 }
 
 --- CV RUBRICS ---
@@ -57,65 +74,114 @@ func buildRubricPrompt(r entity.EvaluationRubrics, cv *dto.CVResponse) string {
 
 	sb.WriteString("\nReturn only valid JSON — no markdown or explanations outside the JSON.\n")
 
-	sb.WriteString(fmt.Sprintf("\nCV Title: %s\n", cv.Title))
+	if len(cv.Facts) > 0 {
+		sb.WriteString("\n--- VERIFIED FACTS ---\n")
+		sb.WriteString("Computed from the CV, use these instead of estimating them yourself:\n")
+		for _, f := range cv.Facts {
+			sb.WriteString("- " + f + "\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	if cv.Title != "" {
+		sb.WriteString(fmt.Sprintf("CV Title: %s\n", cv.Title))
+	}
 	sb.WriteString(fmt.Sprintf("CV Summary: %s\n", cv.Summary))
-	sb.WriteString(fmt.Sprintf("File Path (reference only): %s\n", cv.FilePath))
+	if cv.FilePath != "" {
+		sb.WriteString(fmt.Sprintf("File Path (reference only): %s\n", cv.FilePath))
+	}
+	if len(cv.Contacts) > 0 {
+		sb.WriteString("Profile Links (reference only):\n")
+		for _, c := range cv.Contacts {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", c.Kind, c.Value))
+		}
+	}
 
 	return sb.String()
 }
 
-func GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
-	fmt.Printf("\ntext: %v\n", text)
-	if GemniClient == nil {
-		return nil, fmt.Errorf("gemini client not initialized")
-	}
+// EmbeddingCache is consulted before embedding and filled after, see
+// SetEmbeddingCache.
+type EmbeddingCache interface {
+	Embed(ctx context.Context, texts []string, embed embedcache.EmbedFunc) ([][]float32, error)
+}
+
+var embeddingCache EmbeddingCache
 
-	model := "text-embedding-004"
-	outD := int32(768)
+// SetEmbeddingCache makes GenerateEmbedding and GenerateEmbeddings go through
+// cache; nil embeds every text again.
+func SetEmbeddingCache(cache EmbeddingCache) {
+	embeddingCache = cache
+}
 
-	result, err := GemniClient.Models.EmbedContent(ctx,
-		model,
-		genai.Text(text),
-		&genai.EmbedContentConfig{OutputDimensionality: &outD},
-	)
+func GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := GenerateEmbeddings(ctx, []string{text})
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate embedding: %w", err)
+		return nil, err
 	}
+	return vectors[0], nil
+}
 
-	if result == nil || len(result.Embeddings) == 0 {
-		return nil, fmt.Errorf("empty embedding response or values")
-	}
+// embedBatchSize is the most texts the API embeds in one call.
+const embedBatchSize = 100
 
-	return result.Embeddings[0].Values, nil
+// GenerateEmbeddings embeds several texts, in as few calls as possible; the
+// vectors are in the order of the texts.
+func GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	if embeddingCache != nil {
+		return embeddingCache.Embed(ctx, texts, embedTexts)
+	}
+	return embedTexts(ctx, texts)
 }
 
-func EvaluateCV(ctx context.Context, cv *dto.CVResponse) (*dto.CVEvaluationResponse, error) {
+func embedTexts(ctx context.Context, texts []string) ([][]float32, error) {
 	if GemniClient == nil {
 		return nil, fmt.Errorf("gemini client not initialized")
 	}
-	rubrics := entity.NewDefaultRubrics()
-	prompt := buildRubricPrompt(rubrics, cv)
 
-	model := "gemini-2.0-flash"
-
-	resp, err := GemniClient.Models.GenerateContent(ctx, model, genai.Text(prompt),
-		&genai.GenerateContentConfig{},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("gemini content generation failed: %w", err)
+	outD := int32(EmbeddingDim)
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embedBatchSize {
+		batch := texts[start:min(start+embedBatchSize, len(texts))]
+		contents := make([]*genai.Content, len(batch))
+		for i, text := range batch {
+			contents[i] = genai.NewContentFromText(text, genai.RoleUser)
+		}
+
+		result, err := GemniClient.Models.EmbedContent(ctx,
+			EmbeddingModel,
+			contents,
+			&genai.EmbedContentConfig{OutputDimensionality: &outD},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embedding: %w", err)
+		}
+		if result == nil || len(result.Embeddings) != len(batch) {
+			return nil, fmt.Errorf("embedding response does not match the %d texts sent", len(batch))
+		}
+		for _, e := range result.Embeddings {
+			out = append(out, e.Values)
+		}
 	}
+	return out, nil
+}
 
-	if len(resp.Candidates) == 0 {
-		return nil, fmt.Errorf("no response from Gemini")
-	}
+// EvaluateCV evaluates the CV against the default rubrics with Gemini.
+func EvaluateCV(ctx context.Context, cv *dto.CVResponse) (*dto.CVEvaluationResponse, error) {
+	return EvaluateCVWith(ctx, Live, cv)
+}
 
-	// Extract response text
-	content := resp.Candidates[0].Content
-	if len(content.Parts) == 0 {
-		return nil, fmt.Errorf("empty content from Gemini")
+// EvaluateCVWith evaluates the CV using gen, which may be live, recording or
+// replaying.
+func EvaluateCVWith(ctx context.Context, gen Generator, cv *dto.CVResponse) (*dto.CVEvaluationResponse, error) {
+	rubrics := entity.NewDefaultRubrics()
+	prompt := buildRubricPrompt(rubrics, cv)
+
+	text, err := gen.Generate(ctx, EvalModel, prompt)
+	if err != nil {
+		return nil, err
 	}
 
-	text := content.Parts[0].Text
 	// clean texts
 	text = strings.TrimSpace(text)
 	text = strings.TrimPrefix(text, "```json")
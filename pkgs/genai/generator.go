@@ -0,0 +1,132 @@
+package gemini
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// Generator produces a text completion for a prompt. The live implementation
+// calls Gemini; the recording and replaying ones let evaluations run against
+// stored responses.
+type Generator interface {
+	Generate(ctx context.Context, model, prompt string) (string, error)
+}
+
+// Live calls Gemini through the package client.
+var Live Generator = liveGenerator{}
+
+type liveGenerator struct{}
+
+func (liveGenerator) Generate(ctx context.Context, model, prompt string) (string, error) {
+	if GemniClient == nil {
+		return "", fmt.Errorf("gemini client not initialized")
+	}
+
+	resp, err := GemniClient.Models.GenerateContent(ctx, model, genai.Text(prompt),
+		&genai.GenerateContentConfig{},
+	)
+	if err != nil {
+		return "", fmt.Errorf("gemini content generation failed: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 {
+		return "", fmt.Errorf("no response from Gemini")
+	}
+
+	content := resp.Candidates[0].Content
+	if content == nil || len(content.Parts) == 0 {
+		return "", fmt.Errorf("empty content from Gemini")
+	}
+
+	return content.Parts[0].Text, nil
+}
+
+// ErrNoRecording is returned in replay mode when a prompt was never recorded.
+var ErrNoRecording = errors.New("no recorded response for prompt")
+
+// Recording is one stored LLM exchange.
+type Recording struct {
+	Model      string    `json:"model"`
+	Prompt     string    `json:"prompt"`
+	Response   string    `json:"response"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// RecordingKey identifies a prompt; any change to the prompt or model gives a
+// new key, so stale recordings are never replayed for a changed prompt.
+func RecordingKey(model, prompt string) string {
+	sum := sha256.Sum256([]byte(model + "\n" + prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+type recordingGenerator struct {
+	next Generator
+	dir  string
+}
+
+// NewRecordingGenerator passes calls through to next and stores every
+// response in dir.
+func NewRecordingGenerator(next Generator, dir string) Generator {
+	return &recordingGenerator{next: next, dir: dir}
+}
+
+func (g *recordingGenerator) Generate(ctx context.Context, model, prompt string) (string, error) {
+	out, err := g.next.Generate(ctx, model, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(g.dir, 0o755); err != nil {
+		return "", fmt.Errorf("cannot create recordings dir: %w", err)
+	}
+	b, err := json.MarshalIndent(Recording{
+		Model:      model,
+		Prompt:     prompt,
+		Response:   out,
+		RecordedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(g.dir, RecordingKey(model, prompt)+".json")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return "", fmt.Errorf("cannot write recording: %w", err)
+	}
+	return out, nil
+}
+
+type replayGenerator struct {
+	dir string
+}
+
+// NewReplayGenerator answers from responses stored by a recording generator
+// and never calls Gemini.
+func NewReplayGenerator(dir string) Generator {
+	return &replayGenerator{dir: dir}
+}
+
+func (g *replayGenerator) Generate(_ context.Context, model, prompt string) (string, error) {
+	key := RecordingKey(model, prompt)
+	b, err := os.ReadFile(filepath.Join(g.dir, key+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w (key %s)", ErrNoRecording, key)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var rec Recording
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return "", fmt.Errorf("corrupt recording %s: %w", key, err)
+	}
+	return rec.Response, nil
+}
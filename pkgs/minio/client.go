@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"net/url"
-	"path/filepath"
 	"time"
 
 	"github.com/GazDuckington/go-gin/internal/config"
@@ -47,21 +46,17 @@ func EnsureBucket(ctx context.Context, bucketName string) error {
 	return nil
 }
 
-// UploadPDF uploads a PDF file from disk to MinIO
-func UploadPDF(ctx context.Context, bucketName, objectName, filePath string) (minio.UploadInfo, error) {
+// UploadFile uploads a file from disk to MinIO, keeping its content type
+func UploadFile(ctx context.Context, bucketName, objectName, filePath, contentType string) (minio.UploadInfo, error) {
 	if Client == nil {
 		return minio.UploadInfo{}, fmt.Errorf("minio client not initialized")
 	}
 
-	if filepath.Ext(filePath) != ".pdf" {
-		return minio.UploadInfo{}, fmt.Errorf("only PDF files are allowed")
-	}
-
 	info, err := Client.FPutObject(ctx, bucketName, objectName, filePath, minio.PutObjectOptions{
-		ContentType: "application/pdf",
+		ContentType: contentType,
 	})
 	if err != nil {
-		return minio.UploadInfo{}, fmt.Errorf("failed to upload PDF: %w", err)
+		return minio.UploadInfo{}, fmt.Errorf("failed to upload file: %w", err)
 	}
 	return info, nil
 }
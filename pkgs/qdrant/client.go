@@ -2,110 +2,515 @@ package qdrant
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/GazDuckington/go-gin/internal/config"
-	"github.com/GazDuckington/go-gin/internal/models/dto"
-	"github.com/GazDuckington/go-gin/internal/models/entity"
-	"github.com/GazDuckington/go-gin/pkgs/minio"
+	"github.com/GazDuckington/go-gin/pkgs/vectorstore"
+	"github.com/google/uuid"
 	"github.com/qdrant/go-client/qdrant"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-var QdrantClient *qdrant.Client
+// ErrReindexNeeded is returned by Init when the alias does not lead to the
+// collection of the configured embedding model; run cmd/reindex.
+var ErrReindexNeeded = errors.New("reindex needed")
 
-func Init(cfg *config.Config) error {
+// Store is a vectorstore.VectorStore backed by Qdrant. Reads and writes go
+// through an alias so a reindex can switch collections atomically. Chunks
+// are child points in the same collection, linked to their CV by cv_id.
+type Store struct {
+	client     *qdrant.Client
+	alias      string // VECTOR_COLLECTION
+	collection string // versioned collection for the embedding model
+	target     string // what points are read from and written to
+	dim        int
+}
+
+var _ vectorstore.VectorStore = (*Store)(nil)
+
+// CollectionName is the collection vectors of model with dim values are kept
+// in, so vectors of different models never end up in one collection.
+func CollectionName(base, model string, dim int) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '-'
+		}
+	}, model)
+	return fmt.Sprintf("%s_%s_%d", base, slug, dim)
+}
+
+// New connects to Qdrant; call Init to set up the collection and alias.
+func New(cfg *config.Config) (*Store, error) {
 	client, err := qdrant.NewClient(&qdrant.Config{
 		Host:   cfg.QdrantHost,
 		Port:   cfg.QdrantGRPCPort,
 		APIKey: cfg.QdrantKey,
 		UseTLS: false,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &Store{
+		client:     client,
+		alias:      cfg.VectorCollection,
+		collection: CollectionName(cfg.VectorCollection, cfg.EmbeddingModel, cfg.EmbeddingDim),
+		target:     cfg.VectorCollection,
+		dim:        cfg.EmbeddingDim,
+	}, nil
+}
+
+// Collection is the versioned collection of the configured embedding model.
+func (s *Store) Collection() string { return s.collection }
+
+// Direct is a copy of the store that reads and writes the versioned
+// collection instead of the alias, to fill it before switching.
+func (s *Store) Direct() *Store {
+	direct := *s
+	direct.target = s.collection
+	return &direct
+}
+
+// Init creates the versioned collection and points the alias at it on first
+// start. An alias that leads elsewhere, or a collection named like the alias
+// from before collections were versioned, is kept and used as is; Init then
+// returns ErrReindexNeeded.
+func (s *Store) Init(ctx context.Context) error {
+	current, err := s.aliasTarget(ctx)
 	if err != nil {
 		return err
 	}
+	if current != "" {
+		if err := s.checkDim(ctx, current); err != nil {
+			return err
+		}
+		if current != s.collection {
+			return fmt.Errorf("%w: alias %s points at %s, not %s", ErrReindexNeeded, s.alias, current, s.collection)
+		}
+		return s.EnsureCollection(ctx)
+	}
 
-	client.CreateCollection(context.Background(), &qdrant.CreateCollection{
-		CollectionName: cfg.MinioBucket,
-		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
-			Size:     768,
-			Distance: qdrant.Distance_Cosine,
-		}),
-	})
+	legacy, err := s.client.CollectionExists(ctx, s.alias)
+	if err != nil {
+		return err
+	}
+	if legacy {
+		if err := s.checkDim(ctx, s.alias); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s is an unversioned collection", ErrReindexNeeded, s.alias)
+	}
+
+	if err := s.EnsureCollection(ctx); err != nil {
+		return err
+	}
+	return s.client.CreateAlias(ctx, s.alias, s.collection)
+}
 
-	QdrantClient = client
+// EnsureCollection creates the versioned collection when missing, and fails
+// when it holds vectors of another size. Payload indexes are created either
+// way, so collections from before chunking get the chunk ones.
+func (s *Store) EnsureCollection(ctx context.Context) error {
+	exists, err := s.client.CollectionExists(ctx, s.collection)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := s.checkDim(ctx, s.collection); err != nil {
+			return err
+		}
+	} else {
+		err = s.client.CreateCollection(ctx, &qdrant.CreateCollection{
+			CollectionName: s.collection,
+			VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+				Size:     uint64(s.dim),
+				Distance: qdrant.Distance_Cosine,
+			}),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// searches are filtered by owner, job and upload date, and tell CVs
+	// from chunks, which are grouped by CV
+	for field, kind := range map[string]qdrant.FieldType{
+		"user_id":    qdrant.FieldType_FieldTypeKeyword,
+		"job_id":     qdrant.FieldType_FieldTypeKeyword,
+		"created_at": qdrant.FieldType_FieldTypeInteger,
+		"level":      qdrant.FieldType_FieldTypeKeyword,
+		"cv_id":      qdrant.FieldType_FieldTypeKeyword,
+		"chunked":    qdrant.FieldType_FieldTypeBool,
+	} {
+		_, err := s.client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+			CollectionName: s.collection,
+			FieldName:      field,
+			FieldType:      kind.Enum(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to index %s: %w", field, err)
+		}
+	}
 	return nil
 }
 
-func NewCVPoint(cv *entity.CV) *qdrant.PointStruct {
-	return &qdrant.PointStruct{
-		Id:      qdrant.NewIDUUID(cv.ID),
-		Vectors: qdrant.NewVectors(cv.Embedding...),
-		Payload: qdrant.NewValueMap(map[string]any{
-			"user_id": cv.UserID,
-			"title":   cv.Title,
-			"summary": cv.Summary,
-			"file":    cv.FilePath,
-		}),
+// SwitchAlias points the alias at the versioned collection in one atomic
+// update and returns the collection it led to before. The old collection is
+// kept for rolling back. A legacy collection named like the alias has to be
+// dropped to free the name, which only happens with dropLegacy.
+func (s *Store) SwitchAlias(ctx context.Context, dropLegacy bool) (string, error) {
+	previous, err := s.aliasTarget(ctx)
+	if err != nil {
+		return "", err
+	}
+	if previous == s.collection {
+		return previous, nil
+	}
+	if previous != "" {
+		return previous, s.client.UpdateAliases(ctx, []*qdrant.AliasOperations{
+			qdrant.NewAliasDelete(s.alias),
+			qdrant.NewAliasCreate(s.alias, s.collection),
+		})
+	}
+
+	legacy, err := s.client.CollectionExists(ctx, s.alias)
+	if err != nil {
+		return "", err
+	}
+	if legacy {
+		if !dropLegacy {
+			return "", fmt.Errorf("%s is an unversioned collection, drop it to use the name as alias", s.alias)
+		}
+		if err := s.client.DeleteCollection(ctx, s.alias); err != nil {
+			return "", err
+		}
+		previous = s.alias
+	}
+	return previous, s.client.CreateAlias(ctx, s.alias, s.collection)
+}
+
+// aliasTarget is the collection the alias leads to, empty when there is no
+// such alias.
+func (s *Store) aliasTarget(ctx context.Context) (string, error) {
+	aliases, err := s.client.ListAliases(ctx)
+	if err != nil {
+		return "", err
 	}
+	for _, a := range aliases {
+		if a.GetAliasName() == s.alias {
+			return a.GetCollectionName(), nil
+		}
+	}
+	return "", nil
+}
+
+func (s *Store) checkDim(ctx context.Context, collection string) error {
+	info, err := s.client.GetCollectionInfo(ctx, collection)
+	if err != nil {
+		return err
+	}
+	if size := info.GetConfig().GetParams().GetVectorsConfig().GetParams().GetSize(); size != uint64(s.dim) {
+		return fmt.Errorf("%w: collection %s has %d dimensions, EMBEDDING_DIM is %d",
+			vectorstore.ErrDimension, collection, size, s.dim)
+	}
+	return nil
 }
 
-func StoreToQdrant(cv *entity.CV, cfg *config.Config) error {
-	point := NewCVPoint(cv)
+func (s *Store) Name() string { return "qdrant" }
+
+// Payload levels of CV and chunk points. Points stored before chunking have
+// no level and count as CVs. CV points that have chunks are marked chunked.
+const (
+	levelCV    = "cv"
+	levelChunk = "chunk"
+)
+
+// Upsert replaces the chunks of the points: old ones are deleted first, as a
+// CV may now have fewer.
+func (s *Store) Upsert(ctx context.Context, points ...vectorstore.Point) error {
+	structs := make([]*qdrant.PointStruct, 0, len(points))
+	ids := make([]string, 0, len(points))
+	for _, p := range points {
+		if err := vectorstore.CheckPoint(p, s.dim); err != nil {
+			return err
+		}
+		parent, err := uuid.Parse(p.ID)
+		if err != nil {
+			return fmt.Errorf("invalid point ID %q: %w", p.ID, err)
+		}
+		ids = append(ids, p.ID)
+
+		payload := map[string]any{
+			"user_id":      p.UserID,
+			"title":        p.Title,
+			"content_type": p.ContentType,
+			"job_id":       p.JobID,
+			"level":        levelCV,
+		}
+		if !p.CreatedAt.IsZero() {
+			payload["created_at"] = p.CreatedAt.Unix()
+		}
+		cv := maps.Clone(payload)
+		cv["chunked"] = len(p.Chunks) > 0
+		structs = append(structs, &qdrant.PointStruct{
+			Id:      qdrant.NewIDUUID(p.ID),
+			Vectors: qdrant.NewVectors(p.Vector...),
+			Payload: qdrant.NewValueMap(cv),
+		})
+
+		for i, c := range p.Chunks {
+			chunk := maps.Clone(payload)
+			chunk["level"] = levelChunk
+			chunk["cv_id"] = p.ID
+			chunk["section"] = c.Section
+			chunk["position"] = i
+			structs = append(structs, &qdrant.PointStruct{
+				Id:      qdrant.NewIDUUID(uuid.NewSHA1(parent, []byte(strconv.Itoa(i))).String()),
+				Vectors: qdrant.NewVectors(c.Vector...),
+				Payload: qdrant.NewValueMap(chunk),
+			})
+		}
+	}
 
-	_, err := QdrantClient.Upsert(context.Background(), &qdrant.UpsertPoints{
-		CollectionName: cfg.MinioBucket,
-		Points:         []*qdrant.PointStruct{point},
+	if err := s.deleteChunks(ctx, ids); err != nil {
+		return err
+	}
+	_, err := s.client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: s.target,
+		Points:         structs,
 	})
 	return err
 }
 
-func GetFromQdrant(ctx context.Context, cv *entity.CV, cfg *config.Config) (*dto.CVResponse, error) {
-	// Fetch the point by ID
-	resp, err := QdrantClient.Get(ctx, &qdrant.GetPoints{
-		CollectionName: cfg.MinioBucket,
-		Ids: []*qdrant.PointId{
-			qdrant.NewIDUUID(cv.ID), // or NewIDUUID if your ID is a UUID
-		},
-		WithPayload: qdrant.NewWithPayload(true),
-		WithVectors: qdrant.NewWithVectors(true),
+func (s *Store) Get(ctx context.Context, ids ...string) ([]vectorstore.Point, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	resp, err := s.client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: s.target,
+		Ids:            pointIDs(ids),
+		WithPayload:    qdrant.NewWithPayload(true),
+		WithVectors:    qdrant.NewWithVectors(true),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	if len(resp) == 0 {
-		return nil, fmt.Errorf("cv not found in qdrant for id: %s", cv.ID)
+	out := make([]vectorstore.Point, 0, len(resp))
+	for _, r := range resp {
+		p := toPoint(r.GetId(), r.GetPayload())
+		p.Vector = r.GetVectors().GetVector().GetData()
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func (s *Store) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := s.deleteChunks(ctx, ids); err != nil {
+		return err
+	}
+	_, err := s.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: s.target,
+		Points:         qdrant.NewPointsSelector(pointIDs(ids)...),
+	})
+	return err
+}
+
+// deleteChunks deletes the chunk points of the CVs.
+func (s *Store) deleteChunks(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
 	}
+	_, err := s.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: s.target,
+		Points: qdrant.NewPointsSelectorFilter(&qdrant.Filter{
+			Must: []*qdrant.Condition{
+				qdrant.NewMatchKeyword("level", levelChunk),
+				qdrant.NewMatchKeywords("cv_id", ids...),
+			},
+		}),
+	})
+	return err
+}
 
-	point := resp[0]
-	payload := point.GetPayload()
+var hitPayload = qdrant.NewWithPayloadInclude("user_id", "job_id", "title", "content_type", "created_at", "cv_id")
 
-	var file_path string
+func (s *Store) Search(ctx context.Context, q vectorstore.Query) ([]vectorstore.Hit, error) {
+	if q.Aggregate.Mode != "" && q.Like == "" {
+		return s.searchChunks(ctx, q)
+	}
 
-	fp, err := minio.GetPresignedURL(ctx, cfg.MinioBucket, payload["file"].GetStringValue(), 1*time.Hour)
-	if err != nil {
-		cfg.Logger.Warnf("[qdrant] failed to get presignedurl: %v", err)
-		// fallback to the original file path if presign fails
-		file_path = payload["file"].GetStringValue()
+	filter := toFilter(q.Filter, false)
+	filter.MustNot = append(filter.MustNot, qdrant.NewMatchKeyword("level", levelChunk))
+	req := &qdrant.QueryPoints{
+		CollectionName: s.target,
+		Filter:         filter,
+		Limit:          qdrant.PtrOf(uint64(q.Limit)),
+		Offset:         qdrant.PtrOf(uint64(q.Offset)),
+		WithPayload:    hitPayload,
+	}
+	if q.Like != "" {
+		req.Query = qdrant.NewQueryID(qdrant.NewIDUUID(q.Like))
 	} else {
-		// use presigned URL if success
-		file_path = fp
+		if err := vectorstore.CheckDim(q.Vector, s.dim); err != nil {
+			return nil, err
+		}
+		req.Query = qdrant.NewQueryDense(q.Vector)
+	}
+	if q.Threshold > 0 {
+		req.ScoreThreshold = qdrant.PtrOf(float32(q.Threshold))
+	}
+
+	points, err := s.client.Query(ctx, req)
+	if q.Like != "" && status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("%w: %s", vectorstore.ErrNotFound, q.Like)
+	}
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]vectorstore.Hit, 0, len(points))
+	for _, p := range points {
+		hits = append(hits, vectorstore.Hit{
+			Point: toPoint(p.GetId(), p.GetPayload()),
+			Score: float64(p.GetScore()),
+		})
+	}
+	return hits, nil
+}
+
+// searchChunks groups the nearest chunks by CV and ranks the CVs by
+// q.Aggregate. Qdrant orders the groups by their best chunk, so with "mean"
+// the CVs are picked by their best chunk and then reordered. CVs without
+// chunks, including those stored before chunking, are searched by their CV
+// vector as if it were their only chunk.
+func (s *Store) searchChunks(ctx context.Context, q vectorstore.Query) ([]vectorstore.Hit, error) {
+	if err := vectorstore.CheckDim(q.Vector, s.dim); err != nil {
+		return nil, err
+	}
+	groupSize := 1
+	if q.Aggregate.Mode == vectorstore.AggregateMean {
+		groupSize = max(q.Aggregate.TopK, 1)
+	}
+	filter := toFilter(q.Filter, true)
+	filter.Must = append(filter.Must, qdrant.NewMatchKeyword("level", levelChunk))
+
+	groups, err := s.client.QueryGroups(ctx, &qdrant.QueryPointGroups{
+		CollectionName: s.target,
+		Query:          qdrant.NewQueryDense(q.Vector),
+		Filter:         filter,
+		GroupBy:        "cv_id",
+		GroupSize:      qdrant.PtrOf(uint64(groupSize)),
+		Limit:          qdrant.PtrOf(uint64(q.Offset + q.Limit)),
+		WithPayload:    hitPayload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var scores []vectorstore.ChunkScore
+	payloads := map[string]vectorstore.Point{}
+	for _, g := range groups {
+		for _, h := range g.GetHits() {
+			p := toPoint(h.GetId(), h.GetPayload())
+			p.ID = h.GetPayload()["cv_id"].GetStringValue()
+			payloads[p.ID] = p
+			scores = append(scores, vectorstore.ChunkScore{ID: p.ID, Score: float64(h.GetScore())})
+		}
+	}
+
+	unchunked := toFilter(q.Filter, false)
+	unchunked.MustNot = append(unchunked.MustNot,
+		qdrant.NewMatchKeyword("level", levelChunk),
+		qdrant.NewMatchBool("chunked", true),
+	)
+	points, err := s.client.Query(ctx, &qdrant.QueryPoints{
+		CollectionName: s.target,
+		Query:          qdrant.NewQueryDense(q.Vector),
+		Filter:         unchunked,
+		Limit:          qdrant.PtrOf(uint64(q.Offset + q.Limit)),
+		WithPayload:    hitPayload,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range points {
+		p := toPoint(h.GetId(), h.GetPayload())
+		payloads[p.ID] = p
+		scores = append(scores, vectorstore.ChunkScore{ID: p.ID, Score: float64(h.GetScore())})
 	}
 
-	// Map payload back into entity.CV
-	resCv := &dto.CVResponse{
-		ID:       point.Id.GetUuid(), // helper below
-		Title:    payload["title"].GetStringValue(),
-		Summary:  payload["summary"].GetStringValue(),
-		FilePath: file_path,
-		UserID:   payload["user_id"].GetStringValue(),
+	ranked := vectorstore.Page(q.Aggregate.Rank(scores), q)
+	hits := make([]vectorstore.Hit, len(ranked))
+	for i, r := range ranked {
+		hits[i] = vectorstore.Hit{Point: payloads[r.ID], Score: r.Score}
+	}
+	return hits, nil
+}
+
+// toFilter translates a filter for CV points, or for chunk points, which
+// point at their CV by cv_id instead of their ID.
+func toFilter(f vectorstore.Filter, chunks bool) *qdrant.Filter {
+	filter := &qdrant.Filter{}
+	if f.UserID != "" {
+		filter.Must = append(filter.Must, qdrant.NewMatchKeyword("user_id", f.UserID))
+	}
+	if f.JobID != "" {
+		filter.Must = append(filter.Must, qdrant.NewMatchKeyword("job_id", f.JobID))
 	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		r := &qdrant.Range{}
+		if !f.Since.IsZero() {
+			r.Gte = qdrant.PtrOf(float64(f.Since.Unix()))
+		}
+		if !f.Until.IsZero() {
+			r.Lt = qdrant.PtrOf(float64(f.Until.Unix()))
+		}
+		filter.Must = append(filter.Must, qdrant.NewRange("created_at", r))
+	}
+	if f.ExcludeUserID != "" {
+		filter.MustNot = append(filter.MustNot, qdrant.NewMatchKeyword("user_id", f.ExcludeUserID))
+	}
+	if len(f.ExcludeIDs) > 0 {
+		if chunks {
+			filter.MustNot = append(filter.MustNot, qdrant.NewMatchKeywords("cv_id", f.ExcludeIDs...))
+		} else {
+			filter.MustNot = append(filter.MustNot, qdrant.NewHasID(pointIDs(f.ExcludeIDs)...))
+		}
+	}
+	return filter
+}
 
-	// Convert vector back to []float32
-	if v := point.Vectors; v != nil {
-		resCv.Embedding = v.GetVector().Data
+func toPoint(id *qdrant.PointId, payload map[string]*qdrant.Value) vectorstore.Point {
+	p := vectorstore.Point{
+		ID:          id.GetUuid(),
+		UserID:      payload["user_id"].GetStringValue(),
+		JobID:       payload["job_id"].GetStringValue(),
+		Title:       payload["title"].GetStringValue(),
+		ContentType: payload["content_type"].GetStringValue(),
 	}
+	if ts := payload["created_at"].GetIntegerValue(); ts > 0 {
+		p.CreatedAt = time.Unix(ts, 0).UTC()
+	}
+	return p
+}
 
-	return resCv, nil
+func pointIDs(ids []string) []*qdrant.PointId {
+	out := make([]*qdrant.PointId, len(ids))
+	for i, id := range ids {
+		out[i] = qdrant.NewIDUUID(id)
+	}
+	return out
 }
@@ -0,0 +1,191 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// identityLabels are "Label: value" lines that describe who the candidate is
+// rather than what they can do. Both English and Indonesian CVs are covered.
+var identityLabels = []struct {
+	placeholder string
+	re          *regexp.Regexp
+}{
+	{"[NAME]", regexp.MustCompile(`(?im)^\s*(?:full\s+name|name|nama(?:\s+lengkap)?)\s*:\s*(.+)$`)},
+	{"[GENDER]", regexp.MustCompile(`(?im)^\s*(?:gender|sex|jenis\s+kelamin)\s*:\s*(.+)$`)},
+	{"[DATE_OF_BIRTH]", regexp.MustCompile(`(?im)^\s*(?:date\s+of\s+birth|birth\s*date|dob|born|place,?\s*date\s+of\s+birth|tempat,?\s*tanggal\s+lahir|tanggal\s+lahir|ttl)\s*:\s*(.+)$`)},
+	{"[AGE]", regexp.MustCompile(`(?im)^\s*(?:age|usia|umur)\s*:\s*(.+)$`)},
+	{"[NATIONALITY]", regexp.MustCompile(`(?im)^\s*(?:nationality|citizenship|kewarganegaraan)\s*:\s*(.+)$`)},
+	{"[RELIGION]", regexp.MustCompile(`(?im)^\s*(?:religion|agama)\s*:\s*(.+)$`)},
+	{"[MARITAL_STATUS]", regexp.MustCompile(`(?im)^\s*(?:marital\s+status|status\s+(?:pernikahan|perkawinan))\s*:\s*(.+)$`)},
+	{"[PHOTO]", regexp.MustCompile(`(?im)^\s*(?:photo|picture|foto)\s*:\s*(.+)$`)},
+}
+
+var (
+	inlineAge  = regexp.MustCompile(`(?i)\b\d{2}\s*(?:years?\s+old|y\.?o\.?|tahun)\b`)
+	honorifics = regexp.MustCompile(`\b(?:Mr|Mrs|Ms|Miss|Bapak|Ibu|Sdr|Sdri)\.?\s+`)
+)
+
+// Anonymize strips identity attributes (name, gender, age, date of birth,
+// nationality, religion, marital status, photo captions) so an evaluator
+// cannot be swayed by them. names are extra known names for the candidate,
+// e.g. from their profile. The first line of the CV is only taken as a
+// spelling of the name when it matches a known or labelled one, so headings
+// and job titles are left alone.
+//
+// Full names are replaced case-insensitively; their single parts only as
+// written, at word boundaries, so a name like "Will" does not eat the verb.
+// Every distinct name gets its own placeholder: [NAME], [NAME_2], ...
+func Anonymize(text string, names ...string) (string, Mapping) {
+	mapping := Mapping{}
+	if text == "" {
+		return text, mapping
+	}
+
+	byName := map[string]string{}
+	var placeholders []string
+	namePlaceholder := func(name string) string {
+		key := nameKey(name)
+		if p, ok := byName[key]; ok {
+			return p
+		}
+		p := "[NAME]"
+		if len(placeholders) > 0 {
+			p = fmt.Sprintf("[NAME_%d]", len(placeholders)+1)
+		}
+		byName[key] = p
+		placeholders = append(placeholders, p)
+		mapping[p] = name
+		return p
+	}
+
+	for _, l := range identityLabels {
+		text = replace(text, detector{re: l.re, group: 1}, func(_ Kind, value string) string {
+			if l.placeholder == "[NAME]" {
+				names = append(names, value)
+				return namePlaceholder(value)
+			}
+			mapping[l.placeholder] = value
+			return l.placeholder
+		})
+	}
+
+	var known []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" && !strings.HasPrefix(name, "[") {
+			known = append(known, name)
+		}
+	}
+	if first := firstLine(text); looksLikeName(first) {
+		for _, name := range known {
+			if matchesName(first, name) {
+				// another spelling of the same person, e.g. "JOHN A. DOE"
+				byName[nameKey(first)] = namePlaceholder(name)
+				known = append(known, first)
+				break
+			}
+		}
+	}
+
+	for _, name := range known {
+		parts := strings.Fields(name)
+		quoted := make([]string, len(parts))
+		for i, p := range parts {
+			quoted[i] = regexp.QuoteMeta(p)
+		}
+		re := regexp.MustCompile(`(?i)\b` + strings.Join(quoted, `\s+`) + `\b`)
+		text = re.ReplaceAllString(text, namePlaceholder(name))
+	}
+	// then the parts on their own ("Doe's project"), only as capitalised
+	for _, name := range known {
+		p := namePlaceholder(name)
+		for _, part := range strings.Fields(name) {
+			r := []rune(part)
+			if len(r) < 3 || !unicode.IsUpper(r[0]) {
+				continue
+			}
+			re := regexp.MustCompile(`\b` + regexp.QuoteMeta(part) + `\b`)
+			text = re.ReplaceAllString(text, p)
+		}
+	}
+	for _, p := range placeholders {
+		text = strings.ReplaceAll(text, p+" "+p, p)
+	}
+
+	text = inlineAge.ReplaceAllStringFunc(text, func(s string) string {
+		mapping["[AGE]"] = s
+		return "[AGE]"
+	})
+	text = honorifics.ReplaceAllString(text, "")
+
+	return text, mapping
+}
+
+// matchesName reports whether line spells name: every word of the name
+// appears in it, ignoring case, with at most two extra words such as middle
+// names or initials.
+func matchesName(line, name string) bool {
+	fields := strings.Fields(nameKey(line))
+	parts := strings.Fields(nameKey(name))
+	if len(parts) == 0 || len(fields) > len(parts)+2 {
+		return false
+	}
+	words := map[string]bool{}
+	for _, w := range fields {
+		words[strings.Trim(w, ".,")] = true
+	}
+	for _, p := range parts {
+		if !words[strings.Trim(p, ".,")] {
+			return false
+		}
+	}
+	return true
+}
+
+// nameKey folds case and spacing so spellings of one name share a placeholder.
+func nameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// firstLine is the first non-empty line, without a Markdown heading marker
+// as produced by layout extraction.
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(line, "# ")); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// headings that look like names but are not
+var notNames = map[string]bool{
+	"curriculum": true, "vitae": true, "resume": true, "résumé": true,
+	"daftar": true, "riwayat": true, "hidup": true, "profile": true,
+}
+
+// looksLikeName accepts 2-4 capitalised words with no digits or symbols,
+// which is how most CVs open.
+func looksLikeName(s string) bool {
+	words := strings.Fields(s)
+	if len(words) < 2 || len(words) > 4 {
+		return false
+	}
+	for _, w := range words {
+		if notNames[strings.ToLower(w)] {
+			return false
+		}
+		r := []rune(w)
+		if !unicode.IsUpper(r[0]) {
+			return false
+		}
+		for _, c := range r {
+			if !unicode.IsLetter(c) && c != '.' && c != '\'' && c != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package redact
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnonymize(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		names   []string
+		want    string
+		mapping Mapping
+	}{
+		{
+			name:    "heading on the first line is not a name",
+			in:      "Professional Summary\nExperienced professional. Summary of work.",
+			want:    "Professional Summary\nExperienced professional. Summary of work.",
+			mapping: Mapping{},
+		},
+		{
+			name:    "first line without a known name is kept",
+			in:      "Jane Doe\nBackend engineer",
+			want:    "Jane Doe\nBackend engineer",
+			mapping: Mapping{},
+		},
+		{
+			name:    "name parts are case sensitive",
+			in:      "Will Smith\nI will deliver. Smith led the team.",
+			names:   []string{"Will Smith"},
+			want:    "[NAME]\nI will deliver. [NAME] led the team.",
+			mapping: Mapping{"[NAME]": "Will Smith"},
+		},
+		{
+			name:    "full name is case insensitive",
+			in:      "# JANE DOE\nContact jane doe for details",
+			names:   []string{"Jane Doe"},
+			want:    "# [NAME]\nContact [NAME] for details",
+			mapping: Mapping{"[NAME]": "Jane Doe"},
+		},
+		{
+			name:    "first line with a middle initial",
+			in:      "Jane A. Doe\nJane built it",
+			names:   []string{"Jane Doe"},
+			want:    "[NAME]\n[NAME] built it",
+			mapping: Mapping{"[NAME]": "Jane Doe"},
+		},
+		{
+			name:    "parts inside words are kept",
+			in:      "Dean Martin\nDeanery Martinez",
+			names:   []string{"Dean Martin"},
+			want:    "[NAME]\nDeanery Martinez",
+			mapping: Mapping{"[NAME]": "Dean Martin"},
+		},
+		{
+			name:    "labelled name and profile name keep separate placeholders",
+			in:      "Name: Budi Santoso\nReferee: Siti Rahma\nSiti recommends Budi",
+			names:   []string{"Siti Rahma"},
+			want:    "Name: [NAME]\nReferee: [NAME_2]\n[NAME_2] recommends [NAME]",
+			mapping: Mapping{"[NAME]": "Budi Santoso", "[NAME_2]": "Siti Rahma"},
+		},
+		{
+			name:    "identity labels and inline age",
+			in:      "Gender: Female\nAgama: Islam\nMs. Rina, 27 years old",
+			names:   []string{"Rina Putri"},
+			want:    "Gender: [GENDER]\nAgama: [RELIGION]\n[NAME], [AGE]",
+			mapping: Mapping{"[GENDER]": "Female", "[RELIGION]": "Islam", "[AGE]": "27 years old", "[NAME]": "Rina Putri"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, mapping := Anonymize(tt.in, tt.names...)
+			if got != tt.want {
+				t.Errorf("Anonymize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if !reflect.DeepEqual(mapping, tt.mapping) {
+				t.Errorf("Anonymize(%q) mapping = %v, want %v", tt.in, mapping, tt.mapping)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kind identifies a class of personally identifiable information.
+type Kind string
+
+const (
+	KindEmail      Kind = "email"
+	KindPhone      Kind = "phone"
+	KindAddress    Kind = "address"
+	KindNationalID Kind = "national_id"
+	KindURL        Kind = "url"
+)
+
+// Named policies selectable through REDACTION_POLICY.
+var (
+	PolicyNone     = []Kind{}
+	PolicyStandard = []Kind{KindEmail, KindPhone, KindNationalID, KindAddress}
+	PolicyStrict   = []Kind{KindEmail, KindPhone, KindNationalID, KindAddress, KindURL}
+)
+
+// Patterns for links, email addresses and phone numbers, shared with the
+// contacts package. Table cells rendered as Markdown end a link at "|".
+var (
+	URLPattern   = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>()"'|]+|\b(?:linkedin\.com|github\.com|gitlab\.com|bitbucket\.org)/[^\s<>()"'|]+`)
+	EmailPattern = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}`)
+	PhonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?(?:\(\d{1,4}\)[\s.\-]?)?\d{2,4}(?:[\s.\-]?\d{2,4}){2,4}`)
+)
+
+// detector finds one kind of PII. group selects the submatch that is replaced,
+// so labelled values ("NIK: 3201...") keep their label.
+type detector struct {
+	kind  Kind
+	re    *regexp.Regexp
+	group int
+	valid func(string) bool
+}
+
+// detectors run in this order; URLs go first so emails and digits inside links
+// are not split into separate placeholders.
+var detectors = []detector{
+	{kind: KindURL, re: URLPattern},
+	{kind: KindEmail, re: EmailPattern},
+	{kind: KindNationalID, re: regexp.MustCompile(`(?i)\b(?:NIK|KTP|SSN|NPWP|passport(?:\s+(?:no\.?|number))?)\s*[:#]?\s*([A-Z]{0,3}\d[\dA-Z.\-]{4,23})`), group: 1},
+	{kind: KindNationalID, re: regexp.MustCompile(`\b\d{16}\b|\b\d{3}-\d{2}-\d{4}\b|\b\d{2}\.\d{3}\.\d{3}\.\d-\d{3}\.\d{3}\b`)},
+	{kind: KindPhone, re: PhonePattern, valid: LooksLikePhone},
+	{kind: KindAddress, re: regexp.MustCompile(`(?im)^\s*(?:address|alamat|domicile|domisili)\s*:\s*(.+)$`), group: 1},
+	{kind: KindAddress, re: regexp.MustCompile(`(?i)\b(?:jl\.|jln\.?|jalan)\s+[^\n,]+(?:,[^\n,]+){0,3}`)},
+	{kind: KindAddress, re: regexp.MustCompile(`\b\d{1,5}\s+(?:[A-Z][a-z]+\s){1,3}(?:Street|St\.|Road|Rd\.|Avenue|Ave\.|Boulevard|Blvd\.|Lane|Ln\.|Drive|Dr\.)`)},
+}
+
+// LooksLikePhone reports whether a PhonePattern match has 9 to 15 digits;
+// date ranges, years and other short digit runs do not.
+func LooksLikePhone(s string) bool {
+	digits := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 9 && digits <= 15
+}
+
+// ParsePolicy resolves a policy name ("none", "standard", "strict") or a
+// comma separated list of kinds ("email,phone,url").
+func ParsePolicy(s string) ([]Kind, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "standard":
+		return PolicyStandard, nil
+	case "none", "off":
+		return PolicyNone, nil
+	case "strict":
+		return PolicyStrict, nil
+	}
+
+	known := map[Kind]bool{}
+	for _, k := range PolicyStrict {
+		known[k] = true
+	}
+
+	var kinds []Kind
+	for _, part := range strings.Split(s, ",") {
+		k := Kind(strings.ToLower(strings.TrimSpace(part)))
+		if k == "" {
+			continue
+		}
+		if !known[k] {
+			return nil, fmt.Errorf("unknown redaction kind %q", k)
+		}
+		kinds = append(kinds, k)
+	}
+	return kinds, nil
+}
+
+// Redactor replaces PII with placeholders such as [EMAIL_1].
+type Redactor struct {
+	kinds map[Kind]bool
+}
+
+func New(kinds ...Kind) *Redactor {
+	r := &Redactor{kinds: map[Kind]bool{}}
+	for _, k := range kinds {
+		r.kinds[k] = true
+	}
+	return r
+}
+
+// Enabled reports whether the redactor replaces anything at all.
+func (r *Redactor) Enabled() bool {
+	return r != nil && len(r.kinds) > 0
+}
+
+// Mapping maps placeholders back to the original values. It is never
+// stored: callers restore what came back from the redacted text while they
+// still hold it, such as the feedback of the same evaluation.
+type Mapping map[string]string
+
+// Restore puts the original values back into text, e.g. into LLM feedback
+// that quotes a placeholder.
+func (m Mapping) Restore(text string) string {
+	if len(m) == 0 {
+		return text
+	}
+	// longest first so [EMAIL_10] is not clobbered by [EMAIL_1]
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+
+	pairs := make([]string, 0, len(keys)*2)
+	for _, k := range keys {
+		pairs = append(pairs, k, m[k])
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Redact returns text with every enabled kind of PII replaced. The same value
+// always gets the same placeholder within one document, and since detection is
+// deterministic, redacting the same text twice yields the same mapping.
+func (r *Redactor) Redact(text string) (string, Mapping) {
+	mapping := Mapping{}
+	if !r.Enabled() || text == "" {
+		return text, mapping
+	}
+
+	byValue := map[string]string{}
+	counters := map[Kind]int{}
+
+	placeholder := func(kind Kind, value string) string {
+		key := string(kind) + "\x00" + value
+		if p, ok := byValue[key]; ok {
+			return p
+		}
+		counters[kind]++
+		p := fmt.Sprintf("[%s_%d]", strings.ToUpper(string(kind)), counters[kind])
+		byValue[key] = p
+		mapping[p] = value
+		return p
+	}
+
+	for _, d := range detectors {
+		if !r.kinds[d.kind] {
+			continue
+		}
+		text = replace(text, d, placeholder)
+	}
+	return text, mapping
+}
+
+func replace(text string, d detector, placeholder func(Kind, string) string) string {
+	matches := d.re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[2*d.group], m[2*d.group+1]
+		if start < 0 {
+			continue
+		}
+		value := strings.TrimRight(strings.TrimSpace(text[start:end]), ".,;:")
+		// never re-redact an existing placeholder
+		if value == "" || strings.HasPrefix(value, "[") {
+			continue
+		}
+		if d.valid != nil && !d.valid(value) {
+			continue
+		}
+		// keep whitespace and punctuation captured by greedy patterns
+		end = start + strings.Index(text[start:end], value) + len(value)
+		start = end - len(value)
+
+		sb.WriteString(text[last:start])
+		sb.WriteString(placeholder(d.kind, value))
+		last = end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
@@ -0,0 +1,163 @@
+package redact
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name    string
+		kinds   []Kind
+		in      string
+		want    string
+		mapping Mapping
+	}{
+		{
+			name:    "email",
+			kinds:   PolicyStandard,
+			in:      "Contact: jane.doe+cv@example.co.id",
+			want:    "Contact: [EMAIL_1]",
+			mapping: Mapping{"[EMAIL_1]": "jane.doe+cv@example.co.id"},
+		},
+		{
+			name:    "same value same placeholder",
+			kinds:   PolicyStandard,
+			in:      "a@b.io or a@b.io, else c@d.io",
+			want:    "[EMAIL_1] or [EMAIL_1], else [EMAIL_2]",
+			mapping: Mapping{"[EMAIL_1]": "a@b.io", "[EMAIL_2]": "c@d.io"},
+		},
+		{
+			name:    "international phone",
+			kinds:   PolicyStandard,
+			in:      "Phone +62 812-3456-7890",
+			want:    "Phone [PHONE_1]",
+			mapping: Mapping{"[PHONE_1]": "+62 812-3456-7890"},
+		},
+		{
+			name:    "date range is not a phone",
+			kinds:   PolicyStandard,
+			in:      "Engineer 2019 - 2021, 2021-2023",
+			want:    "Engineer 2019 - 2021, 2021-2023",
+			mapping: Mapping{},
+		},
+		{
+			name:    "labelled NIK keeps its label",
+			kinds:   PolicyStandard,
+			in:      "NIK: 3201234567890001",
+			want:    "NIK: [NATIONAL_ID_1]",
+			mapping: Mapping{"[NATIONAL_ID_1]": "3201234567890001"},
+		},
+		{
+			name:    "labelled passport number",
+			kinds:   PolicyStandard,
+			in:      "Passport No: A1234567",
+			want:    "Passport No: [NATIONAL_ID_1]",
+			mapping: Mapping{"[NATIONAL_ID_1]": "A1234567"},
+		},
+		{
+			name:    "passport label without a number",
+			kinds:   PolicyStandard,
+			in:      "Valid passport holder, willing to relocate",
+			want:    "Valid passport holder, willing to relocate",
+			mapping: Mapping{},
+		},
+		{
+			name:    "bare SSN",
+			kinds:   PolicyStandard,
+			in:      "ssn 123-45-6789",
+			want:    "ssn [NATIONAL_ID_1]",
+			mapping: Mapping{"[NATIONAL_ID_1]": "123-45-6789"},
+		},
+		{
+			name:    "labelled address",
+			kinds:   PolicyStandard,
+			in:      "Address: 12 Baker Street, London\nSkills: Go",
+			want:    "Address: [ADDRESS_1]\nSkills: Go",
+			mapping: Mapping{"[ADDRESS_1]": "12 Baker Street, London"},
+		},
+		{
+			name:    "url kept by standard policy",
+			kinds:   PolicyStandard,
+			in:      "https://github.com/jane",
+			want:    "https://github.com/jane",
+			mapping: Mapping{},
+		},
+		{
+			name:    "url with email inside is one placeholder",
+			kinds:   PolicyStrict,
+			in:      "see https://example.com/u/jane@example.com",
+			want:    "see [URL_1]",
+			mapping: Mapping{"[URL_1]": "https://example.com/u/jane@example.com"},
+		},
+		{
+			name:    "disabled",
+			kinds:   PolicyNone,
+			in:      "jane@example.com",
+			want:    "jane@example.com",
+			mapping: Mapping{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, mapping := New(tt.kinds...).Redact(tt.in)
+			if got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if !reflect.DeepEqual(mapping, tt.mapping) {
+				t.Errorf("Redact(%q) mapping = %v, want %v", tt.in, mapping, tt.mapping)
+			}
+			if restored := mapping.Restore(got); restored != tt.in {
+				t.Errorf("Restore = %q, want %q", restored, tt.in)
+			}
+		})
+	}
+}
+
+func TestRedactDeterministic(t *testing.T) {
+	in := "b@x.io a@x.io +1 415 555 0100"
+	r := New(PolicyStrict...)
+	first, m1 := r.Redact(in)
+	second, m2 := r.Redact(in)
+	if first != second || !reflect.DeepEqual(m1, m2) {
+		t.Fatalf("redaction not deterministic: %q %v vs %q %v", first, m1, second, m2)
+	}
+}
+
+func TestRestoreLongestFirst(t *testing.T) {
+	m := Mapping{}
+	for i := 1; i <= 10; i++ {
+		m["[EMAIL_"+strconv.Itoa(i)+"]"] = "user" + strconv.Itoa(i) + "@x.io"
+	}
+	if got := m.Restore("[EMAIL_10] [EMAIL_1]"); got != "user10@x.io user1@x.io" {
+		t.Fatalf("Restore = %q", got)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []Kind
+		wantErr bool
+	}{
+		{in: "", want: PolicyStandard},
+		{in: "Standard", want: PolicyStandard},
+		{in: "off", want: PolicyNone},
+		{in: "strict", want: PolicyStrict},
+		{in: "email, url", want: []Kind{KindEmail, KindURL}},
+		{in: "email,fax", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParsePolicy(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePolicy(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
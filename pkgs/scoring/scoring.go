@@ -0,0 +1,97 @@
+// Package scoring blends the scores of several scorers, each between 0 and 1,
+// into one weighted score and keeps every scorer's share of it.
+package scoring
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Weights are the blend weights per scorer name. They need not sum to one.
+type Weights map[string]float64
+
+// ParseWeights reads "llm=0.6,skills=0.2,experience=0.2".
+func ParseWeights(s string) (Weights, error) {
+	w := Weights{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid weight %q, want name=weight", part)
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("invalid weight %q, want a non-negative number", part)
+		}
+		w[strings.ToLower(strings.TrimSpace(name))] = f
+	}
+	return w, nil
+}
+
+// Contribution is one scorer's part of a blended score. Share is its weight
+// normalized over the scorers that applied; Value is Share * Score.
+type Contribution struct {
+	Scorer  string  `json:"scorer"`
+	Score   float64 `json:"score"`
+	Weight  float64 `json:"weight"`
+	Share   float64 `json:"share"`
+	Value   float64 `json:"contribution"`
+	Skipped bool    `json:"skipped,omitempty"` // the scorer did not apply
+}
+
+// Result is a blended score with every scorer's contribution.
+type Result struct {
+	Score         float64        `json:"score"`
+	Contributions []Contribution `json:"contributions"`
+}
+
+// Blend combines scores by weight. Weighted scorers missing from scores are
+// listed as skipped and the remaining weights are renormalized; scores
+// without a weight are listed with no share.
+func Blend(w Weights, scores map[string]float64) Result {
+	names := map[string]bool{}
+	for n := range w {
+		names[n] = true
+	}
+	for n := range scores {
+		names[n] = true
+	}
+
+	var total float64
+	for n, s := range w {
+		if _, ok := scores[n]; ok {
+			total += s
+		}
+	}
+
+	res := Result{Contributions: []Contribution{}}
+	for n := range names {
+		c := Contribution{Scorer: n, Weight: w[n]}
+		score, ok := scores[n]
+		switch {
+		case !ok:
+			c.Skipped = true
+		case total > 0:
+			c.Score = score
+			c.Share = w[n] / total
+			c.Value = c.Share * score
+		default:
+			c.Score = score
+		}
+		res.Score += c.Value
+		res.Contributions = append(res.Contributions, c)
+	}
+	sort.Slice(res.Contributions, func(i, j int) bool {
+		a, b := res.Contributions[i], res.Contributions[j]
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		return a.Scorer < b.Scorer
+	})
+	return res
+}
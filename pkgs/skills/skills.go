@@ -0,0 +1,292 @@
+// Package skills detects the skills mentioned in a CV, maps them to canonical
+// names using a taxonomy of synonyms and categories, and scores them against
+// the skills a job requires.
+package skills
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/GazDuckington/go-gin/pkgs/cvparse"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed taxonomy.json
+var defaultTaxonomy []byte
+
+// Entry is one canonical skill in a taxonomy file. The name itself is always
+// a synonym; CaseSensitive makes it match only as written, for names that are
+// also common words ("Go", "React"). Ambiguous names are used as everyday
+// words or letters even when capitalized ("Go to market", "grade C") and
+// count only in a skills section or next to a list separator. Synonyms
+// always ignore case and are never ambiguous.
+type Entry struct {
+	Name          string   `json:"name" yaml:"name"`
+	Category      string   `json:"category" yaml:"category"`
+	CaseSensitive bool     `json:"case_sensitive" yaml:"case_sensitive"`
+	Ambiguous     bool     `json:"ambiguous" yaml:"ambiguous"`
+	Synonyms      []string `json:"synonyms" yaml:"synonyms"`
+}
+
+// Skill is a canonical skill found in a text.
+type Skill struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Mentions int    `json:"mentions"`
+}
+
+// Taxonomy matches synonyms to canonical skills.
+type Taxonomy struct {
+	entries []Entry
+	terms   []term
+	byName  map[string]int // lowercased name or synonym -> entry
+}
+
+type term struct {
+	entry     int
+	re        *regexp.Regexp
+	ambiguous bool
+}
+
+// Parse reads a taxonomy, JSON or YAML, of the form
+// {"skills": [{"name": "PostgreSQL", "category": "database", "synonyms": ["postgres"]}]}.
+func Parse(data []byte, yamlFormat bool) (*Taxonomy, error) {
+	var doc struct {
+		Skills []Entry `json:"skills" yaml:"skills"`
+	}
+	var err error
+	if yamlFormat {
+		err = yaml.Unmarshal(data, &doc)
+	} else {
+		err = json.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid skills taxonomy: %w", err)
+	}
+	return New(doc.Skills)
+}
+
+// Load reads a taxonomy file; .yaml and .yml files are YAML, anything else JSON.
+func Load(path string) (*Taxonomy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read skills taxonomy: %w", err)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	return Parse(data, ext == ".yaml" || ext == ".yml")
+}
+
+// New builds a taxonomy from entries. A synonym may belong to one skill only.
+func New(entries []Entry) (*Taxonomy, error) {
+	t := &Taxonomy{entries: entries, byName: map[string]int{}}
+	for i, e := range entries {
+		if strings.TrimSpace(e.Name) == "" {
+			return nil, fmt.Errorf("skills taxonomy: entry %d has no name", i)
+		}
+		t.add(i, e.Name, e.CaseSensitive, e.Ambiguous)
+		for _, s := range e.Synonyms {
+			t.add(i, s, false, false)
+		}
+	}
+	for i, e := range entries {
+		for _, s := range append([]string{e.Name}, e.Synonyms...) {
+			if j := t.byName[strings.ToLower(strings.TrimSpace(s))]; j != i {
+				return nil, fmt.Errorf("skills taxonomy: %q is listed under both %s and %s", s, entries[j].Name, e.Name)
+			}
+		}
+	}
+	return t, nil
+}
+
+func (t *Taxonomy) add(entry int, s string, caseSensitive, ambiguous bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return
+	}
+	key := strings.ToLower(s)
+	if _, ok := t.byName[key]; !ok {
+		t.byName[key] = entry
+	}
+	t.terms = append(t.terms, term{entry: entry, re: termPattern(s, caseSensitive), ambiguous: ambiguous})
+}
+
+// termPattern matches s as a whole term. Letters, digits, '+' and '#' next to
+// it mean it is part of a longer word ("C" in "C++", "Java" in "JavaScript"),
+// and so does a '.' before it ("js" in "Node.js") and a '&' next to a single
+// letter ("R" in "R&D").
+func termPattern(s string, caseSensitive bool) *regexp.Regexp {
+	flags := "(?i)"
+	if caseSensitive {
+		flags = ""
+	}
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = regexp.QuoteMeta(w)
+	}
+	before, after := `\pL\pN+#`, `\pL\pN+#`
+	if r, _ := utf8.DecodeRuneInString(s); unicode.IsLetter(r) || unicode.IsDigit(r) {
+		before += `.`
+	}
+	if utf8.RuneCountInString(s) == 1 {
+		before += `&`
+		after += `&`
+	}
+	return regexp.MustCompile(flags + `(?:^|[^` + before + `])(` + strings.Join(words, `[\s\-]+`) + `)(?:$|[^` + after + `])`)
+}
+
+// Find returns the canonical skills mentioned in text, most mentioned first.
+func (t *Taxonomy) Find(text string) []Skill {
+	var sections []cvparse.Section
+	counts := map[int]int{}
+	for _, tm := range t.terms {
+		if !tm.ambiguous {
+			counts[tm.entry] += len(tm.re.FindAllStringIndex(text, -1))
+			continue
+		}
+		if sections == nil {
+			sections = cvparse.Split(text)
+		}
+		for _, sec := range sections {
+			for _, m := range tm.re.FindAllStringSubmatchIndex(sec.Content, -1) {
+				if sec.Kind == cvparse.SectionSkills || inList(sec.Content, m[2], m[3]) {
+					counts[tm.entry]++
+				}
+			}
+		}
+	}
+	for i, n := range counts {
+		if n == 0 {
+			delete(counts, i)
+		}
+	}
+
+	found := make([]Skill, 0, len(counts))
+	for i, n := range counts {
+		found = append(found, Skill{Name: t.entries[i].Name, Category: t.entries[i].Category, Mentions: n})
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Mentions != found[j].Mentions {
+			return found[i].Mentions > found[j].Mentions
+		}
+		return found[i].Name < found[j].Name
+	})
+	return found
+}
+
+// listSeparators set a term off as an item of a list, "Go, PostgreSQL" or
+// "C/C++", rather than a word in a sentence.
+const listSeparators = ",;/|()•·"
+
+// inList reports whether text[start:end] has a list separator next to it,
+// spaces aside.
+func inList(text string, start, end int) bool {
+	left := strings.TrimRight(text[:start], " \t")
+	right := strings.TrimLeft(text[end:], " \t")
+	if r, _ := utf8.DecodeLastRuneInString(left); r != utf8.RuneError && strings.ContainsRune(listSeparators, r) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(right)
+	return r != utf8.RuneError && strings.ContainsRune(listSeparators, r)
+}
+
+// Canonical maps a skill name or synonym to its canonical name.
+func (t *Taxonomy) Canonical(name string) (string, bool) {
+	if i, ok := t.byName[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return t.entries[i].Name, true
+	}
+	return strings.TrimSpace(name), false
+}
+
+// Normalize canonicalizes a list of skill names, dropping blanks and
+// duplicates. Names outside the taxonomy are kept as given.
+func (t *Taxonomy) Normalize(names []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, n := range names {
+		c, _ := t.Canonical(n)
+		if c == "" || seen[strings.ToLower(c)] {
+			continue
+		}
+		seen[strings.ToLower(c)] = true
+		out = append(out, c)
+	}
+	return out
+}
+
+// MatchResult compares the skills a job requires with those in a CV. Score
+// is the share of required skills present, 0 to 1.
+type MatchResult struct {
+	Required []string `json:"required"`
+	Matched  []string `json:"matched"`
+	Missing  []string `json:"missing"`
+	Score    float64  `json:"score"`
+}
+
+// Match checks each required skill against the skills found in a CV.
+// Required skills outside the taxonomy are looked up in text as a term.
+func (t *Taxonomy) Match(required []string, found []Skill, text string) MatchResult {
+	have := map[string]bool{}
+	for _, s := range found {
+		have[strings.ToLower(s.Name)] = true
+	}
+
+	res := MatchResult{Required: t.Normalize(required), Matched: []string{}, Missing: []string{}}
+	for _, r := range res.Required {
+		ok := have[strings.ToLower(r)]
+		if _, known := t.Canonical(r); !known {
+			ok = termPattern(r, false).MatchString(text)
+		}
+		if ok {
+			res.Matched = append(res.Matched, r)
+		} else {
+			res.Missing = append(res.Missing, r)
+		}
+	}
+	if len(res.Required) > 0 {
+		res.Score = float64(len(res.Matched)) / float64(len(res.Required))
+	}
+	return res
+}
+
+var (
+	defaultMu sync.RWMutex
+	current   *Taxonomy
+)
+
+// SetDefault replaces the taxonomy returned by Default.
+func SetDefault(t *Taxonomy) {
+	defaultMu.Lock()
+	current = t
+	defaultMu.Unlock()
+}
+
+// Default is the taxonomy set with SetDefault, or the built-in one.
+func Default() *Taxonomy {
+	defaultMu.RLock()
+	t := current
+	defaultMu.RUnlock()
+	if t != nil {
+		return t
+	}
+
+	t, err := Parse(defaultTaxonomy, false)
+	if err != nil {
+		panic(err) // the embedded file is part of the build
+	}
+	defaultMu.Lock()
+	if current == nil {
+		current = t
+	}
+	t = current
+	defaultMu.Unlock()
+	return t
+}
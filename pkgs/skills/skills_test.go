@@ -0,0 +1,38 @@
+package skills
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	tax := Default()
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "js synonym", text: "Wrote JS and TS for the dashboard", want: []string{"JavaScript", "TypeScript"}},
+		{name: "js inside framework names", text: "Built APIs with Node.js and a Next.js frontend", want: []string{"Next.js", "Node.js"}},
+		{name: "dotted names", text: "Services in .NET and asp.net", want: []string{".NET"}},
+		{name: "case sensitive name", text: "Rust services; the rust stays", want: []string{"Rust"}},
+		{name: "letter before an ampersand", text: "Led the R&D team", want: nil},
+		{name: "letter grade", text: "Graduated with grade C in maths", want: nil},
+		{name: "common word", text: "Go to market plan for the Spring 2019 semester", want: nil},
+		{name: "ambiguous in a list", text: "Languages: Go, Python, C/C++", want: []string{"C", "C++", "Go", "Python"}},
+		{name: "ambiguous in parentheses", text: "Built services in Go (Gin) with Spring", want: []string{"Gin", "Go"}},
+		{name: "ambiguous in a skills section", text: "Summary\nGo to person for the team\n\nSkills\nGo\nR\nSpring Boot", want: []string{"Spring", "Go", "R"}},
+		{name: "longer words", text: "C++ and JavaScript", want: []string{"C++", "JavaScript"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range tax.Find(tt.text) {
+				got = append(got, s.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// MeanAbsoluteError of two equally long series; NaN when empty.
+func MeanAbsoluteError(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return math.NaN()
+	}
+	var sum float64
+	for i := range a {
+		sum += math.Abs(a[i] - b[i])
+	}
+	return sum / float64(len(a))
+}
+
+// Spearman is the rank correlation of a and b, with ties given their average
+// rank. NaN when there are fewer than two pairs or either side is constant.
+func Spearman(a, b []float64) float64 {
+	if len(a) < 2 || len(a) != len(b) {
+		return math.NaN()
+	}
+	return pearson(ranks(a), ranks(b))
+}
+
+func ranks(xs []float64) []float64 {
+	idx := make([]int, len(xs))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return xs[idx[i]] < xs[idx[j]] })
+
+	out := make([]float64, len(xs))
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && xs[idx[j+1]] == xs[idx[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			out[idx[k]] = avg
+		}
+		i = j + 1
+	}
+	return out
+}
+
+func pearson(a, b []float64) float64 {
+	n := float64(len(a))
+	var ma, mb float64
+	for i := range a {
+		ma += a[i]
+		mb += b[i]
+	}
+	ma /= n
+	mb /= n
+
+	var cov, va, vb float64
+	for i := range a {
+		da, db := a[i]-ma, b[i]-mb
+		cov += da * db
+		va += da * da
+		vb += db * db
+	}
+	if va == 0 || vb == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(va*vb)
+}
+
+// CohenKappa measures agreement between two raters assigning each item one
+// of k categories (0..k-1), corrected for chance. NaN when empty; 1 when both
+// raters always use the same single category.
+func CohenKappa(a, b []int, k int) float64 {
+	if len(a) == 0 || len(a) != len(b) || k < 1 {
+		return math.NaN()
+	}
+
+	n := float64(len(a))
+	rowTotals := make([]float64, k)
+	colTotals := make([]float64, k)
+	var agree float64
+	for i := range a {
+		if a[i] < 0 || a[i] >= k || b[i] < 0 || b[i] >= k {
+			return math.NaN()
+		}
+		rowTotals[a[i]]++
+		colTotals[b[i]]++
+		if a[i] == b[i] {
+			agree++
+		}
+	}
+
+	po := agree / n
+	var pe float64
+	for c := 0; c < k; c++ {
+		pe += (rowTotals[c] / n) * (colTotals[c] / n)
+	}
+	if pe == 1 {
+		return 1
+	}
+	return (po - pe) / (1 - pe)
+}
@@ -0,0 +1,47 @@
+package stats
+
+import "sort"
+
+// RankedList is one ranking of IDs, best first, with its fusion weight.
+type RankedList struct {
+	IDs    []string
+	Weight float64
+}
+
+// Fused is an ID with its reciprocal rank fusion score and its 1-based rank
+// in each list, 0 where it is missing.
+type Fused struct {
+	ID    string
+	Score float64
+	Ranks []int
+}
+
+// ReciprocalRankFusion merges rankings: an ID scores the sum of
+// weight / (k + rank) over the lists it appears in. k dampens the lead of top
+// ranks; 60 is the usual choice.
+func ReciprocalRankFusion(k float64, lists ...RankedList) []Fused {
+	byID := map[string]*Fused{}
+	var order []string
+	for li, l := range lists {
+		for r, id := range l.IDs {
+			f, ok := byID[id]
+			if !ok {
+				f = &Fused{ID: id, Ranks: make([]int, len(lists))}
+				byID[id] = f
+				order = append(order, id)
+			}
+			if f.Ranks[li] != 0 {
+				continue // duplicate in the same list
+			}
+			f.Ranks[li] = r + 1
+			f.Score += l.Weight / (k + float64(r+1))
+		}
+	}
+
+	out := make([]Fused, 0, len(order))
+	for _, id := range order {
+		out = append(out, *byID[id])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
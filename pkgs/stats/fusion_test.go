@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestReciprocalRankFusion(t *testing.T) {
+	tests := []struct {
+		name   string
+		k      float64
+		lists  []RankedList
+		want   []string
+		ranks  map[string][]int
+		scores map[string]float64
+	}{
+		{
+			name:  "no lists",
+			k:     60,
+			lists: nil,
+			want:  []string{},
+		},
+		{
+			name:   "single list keeps its order",
+			k:      60,
+			lists:  []RankedList{{IDs: []string{"a", "b", "c"}, Weight: 1}},
+			want:   []string{"a", "b", "c"},
+			scores: map[string]float64{"a": 1.0 / 61, "b": 1.0 / 62, "c": 1.0 / 63},
+		},
+		{
+			name: "agreement beats one top rank",
+			k:    60,
+			lists: []RankedList{
+				{IDs: []string{"a", "b", "c"}, Weight: 1},
+				{IDs: []string{"d", "b", "e"}, Weight: 1},
+			},
+			want: []string{"b", "a", "d", "c", "e"},
+			ranks: map[string][]int{
+				"a": {1, 0}, "b": {2, 2}, "c": {3, 0}, "d": {0, 1}, "e": {0, 3},
+			},
+		},
+		{
+			name: "weights tip the order",
+			k:    60,
+			lists: []RankedList{
+				{IDs: []string{"a", "b"}, Weight: 1},
+				{IDs: []string{"b", "a"}, Weight: 2},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "ties keep first-seen order",
+			k:    60,
+			lists: []RankedList{
+				{IDs: []string{"a", "b"}, Weight: 1},
+				{IDs: []string{"b", "a"}, Weight: 1},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name:   "duplicates in a list count once",
+			k:      0,
+			lists:  []RankedList{{IDs: []string{"a", "a", "b"}, Weight: 1}},
+			want:   []string{"a", "b"},
+			ranks:  map[string][]int{"a": {1}, "b": {3}},
+			scores: map[string]float64{"a": 1, "b": 1.0 / 3},
+		},
+		{
+			name: "zero weight list only records ranks",
+			k:    60,
+			lists: []RankedList{
+				{IDs: []string{"a", "b"}, Weight: 1},
+				{IDs: []string{"b", "c"}, Weight: 0},
+			},
+			want:  []string{"a", "b", "c"},
+			ranks: map[string][]int{"c": {0, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fused := ReciprocalRankFusion(tt.k, tt.lists...)
+			got := make([]string, 0, len(fused))
+			for _, f := range fused {
+				got = append(got, f.ID)
+				if want, ok := tt.ranks[f.ID]; ok && !reflect.DeepEqual(f.Ranks, want) {
+					t.Errorf("ranks of %s = %v, want %v", f.ID, f.Ranks, want)
+				}
+				if want, ok := tt.scores[f.ID]; ok && math.Abs(f.Score-want) > 1e-12 {
+					t.Errorf("score of %s = %v, want %v", f.ID, f.Score, want)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
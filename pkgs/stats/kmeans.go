@@ -0,0 +1,119 @@
+package stats
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// KMeansResult is the outcome of KMeans: the cluster of every vector and
+// the unit length centroid of every cluster.
+type KMeansResult struct {
+	Assign     []int
+	Centroids  [][]float32
+	Iterations int
+}
+
+// KMeans groups vectors into k clusters by cosine similarity (spherical
+// k-means): vectors are compared by direction and centroids are the
+// normalized mean of their members. Centroids are seeded with k-means++ from
+// seed, so the same input always clusters the same way. A cluster that runs
+// empty takes the vector furthest from its centroid. k is capped at the
+// number of vectors; vectors must be equally long.
+func KMeans(vectors [][]float32, k, maxIter int, seed uint64) KMeansResult {
+	k = min(k, len(vectors))
+	if k <= 0 {
+		return KMeansResult{}
+	}
+	unit := make([][]float32, len(vectors))
+	for i, v := range vectors {
+		unit[i] = MeanVector([][]float32{v})
+	}
+
+	rng := rand.New(rand.NewPCG(seed, seed))
+	centroids := seedCentroids(unit, k, rng)
+	assign := make([]int, len(unit))
+	for i := range assign {
+		assign[i] = -1
+	}
+
+	res := KMeansResult{Assign: assign, Centroids: centroids}
+	for res.Iterations < maxIter {
+		res.Iterations++
+		changed := false
+		for i, v := range unit {
+			best, bestSim := 0, math.Inf(-1)
+			for c, centroid := range centroids {
+				if sim := dot(v, centroid); sim > bestSim {
+					best, bestSim = c, sim
+				}
+			}
+			if assign[i] != best {
+				assign[i], changed = best, true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		members := make([][][]float32, k)
+		for i, c := range assign {
+			members[c] = append(members[c], unit[i])
+		}
+		for c := range centroids {
+			if len(members[c]) > 0 {
+				centroids[c] = MeanVector(members[c])
+				continue
+			}
+			far, farSim := 0, math.Inf(1)
+			for i, v := range unit {
+				if sim := dot(v, centroids[assign[i]]); sim < farSim {
+					far, farSim = i, sim
+				}
+			}
+			centroids[c] = unit[far]
+			assign[far] = c
+		}
+	}
+	return res
+}
+
+// seedCentroids picks k distinct starting centroids, each next one with a
+// probability proportional to its squared distance from the nearest picked.
+func seedCentroids(unit [][]float32, k int, rng *rand.Rand) [][]float32 {
+	centroids := [][]float32{unit[rng.IntN(len(unit))]}
+	dist := make([]float64, len(unit))
+	for len(centroids) < k {
+		var total float64
+		for i, v := range unit {
+			// for unit vectors the squared euclidean distance is 2 - 2cos
+			d := math.Max(0, 2-2*dot(v, centroids[len(centroids)-1]))
+			if len(centroids) == 1 || d < dist[i] {
+				dist[i] = d
+			}
+			total += dist[i]
+		}
+		if total == 0 {
+			// the rest are duplicates of picked centroids
+			centroids = append(centroids, unit[rng.IntN(len(unit))])
+			continue
+		}
+		r := rng.Float64() * total
+		next := len(unit) - 1
+		for i, d := range dist {
+			if r -= d; r <= 0 && d > 0 {
+				next = i
+				break
+			}
+		}
+		centroids = append(centroids, unit[next])
+	}
+	return centroids
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+// blobs returns n noisy vectors around each centre, in order, with their
+// centre index.
+func blobs(centres [][]float32, n int, noise float64, seed uint64) ([][]float32, []int) {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	var vectors [][]float32
+	var labels []int
+	for c, centre := range centres {
+		for range n {
+			v := make([]float32, len(centre))
+			for i, x := range centre {
+				v[i] = x + float32(rng.NormFloat64()*noise)
+			}
+			vectors = append(vectors, v)
+			labels = append(labels, c)
+		}
+	}
+	return vectors, labels
+}
+
+// sameClustering reports whether two labellings group the items the same
+// way, whatever the cluster numbers.
+func sameClustering(a, b []int) bool {
+	ab, ba := map[int]int{}, map[int]int{}
+	for i := range a {
+		if x, ok := ab[a[i]]; ok && x != b[i] {
+			return false
+		}
+		if x, ok := ba[b[i]]; ok && x != a[i] {
+			return false
+		}
+		ab[a[i]], ba[b[i]] = b[i], a[i]
+	}
+	return true
+}
+
+func TestKMeans(t *testing.T) {
+	separated, labels := blobs([][]float32{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}, 20, 0.05, 7)
+
+	tests := []struct {
+		name     string
+		vectors  [][]float32
+		k        int
+		clusters int
+		want     []int // expected grouping, nil to skip
+	}{
+		{name: "separated clusters", vectors: separated, k: 3, clusters: 3, want: labels},
+		{
+			name:     "direction not length",
+			vectors:  [][]float32{{1, 0}, {10, 0.5}, {0.1, 0}, {0, 1}, {0.5, 20}, {0, 0.2}},
+			k:        2,
+			clusters: 2,
+			want:     []int{0, 0, 0, 1, 1, 1},
+		},
+		{name: "k capped at vectors", vectors: [][]float32{{1, 0}, {0, 1}}, k: 5, clusters: 2, want: []int{0, 1}},
+		{name: "duplicates", vectors: [][]float32{{1, 0}, {1, 0}, {1, 0}}, k: 2, clusters: 2},
+		{name: "no vectors", vectors: nil, k: 3, clusters: 0},
+		{name: "k zero", vectors: [][]float32{{1, 0}}, k: 0, clusters: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := KMeans(tt.vectors, tt.k, 50, 42)
+			if len(res.Centroids) != tt.clusters {
+				t.Fatalf("got %d centroids, want %d", len(res.Centroids), tt.clusters)
+			}
+			if tt.clusters == 0 {
+				return
+			}
+			if len(res.Assign) != len(tt.vectors) {
+				t.Fatalf("got %d assignments, want %d", len(res.Assign), len(tt.vectors))
+			}
+			for c, centroid := range res.Centroids {
+				if n := math.Sqrt(dot(centroid, centroid)); math.Abs(n-1) > 1e-5 {
+					t.Errorf("centroid %d has length %v", c, n)
+				}
+			}
+			for i, c := range res.Assign {
+				if c < 0 || c >= tt.clusters {
+					t.Errorf("vector %d assigned to cluster %d", i, c)
+				}
+			}
+			if tt.want != nil && !sameClustering(res.Assign, tt.want) {
+				t.Errorf("assign = %v, want grouping %v", res.Assign, tt.want)
+			}
+		})
+	}
+}
+
+func TestKMeansDeterministic(t *testing.T) {
+	vectors, _ := blobs([][]float32{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 1}}, 30, 0.4, 3)
+	first := KMeans(vectors, 4, 50, 99)
+	second := KMeans(vectors, 4, 50, 99)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("same seed gave different clusterings")
+	}
+}
+
+func TestKMeansMaxIterations(t *testing.T) {
+	vectors, _ := blobs([][]float32{{1, 0}, {0, 1}}, 50, 0.5, 11)
+	if res := KMeans(vectors, 2, 1, 1); res.Iterations != 1 {
+		t.Fatalf("Iterations = %d, want 1", res.Iterations)
+	}
+}
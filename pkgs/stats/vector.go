@@ -0,0 +1,61 @@
+package stats
+
+import "math"
+
+// Cosine similarity of two equally long vectors; NaN when either is empty,
+// zero or they differ in length.
+func Cosine(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return math.NaN()
+	}
+	var dot, na, nb float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		na += x * x
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return math.NaN()
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// MeanVector is the unit length mean of the vectors after scaling each to
+// unit length, so every vector weighs the same. Nil without vectors or when
+// their lengths differ.
+func MeanVector(vectors [][]float32) []float32 {
+	if len(vectors) == 0 {
+		return nil
+	}
+	sum := make([]float64, len(vectors[0]))
+	for _, v := range vectors {
+		if len(v) != len(sum) {
+			return nil
+		}
+		var norm float64
+		for _, x := range v {
+			norm += float64(x) * float64(x)
+		}
+		if norm == 0 {
+			continue
+		}
+		norm = math.Sqrt(norm)
+		for i, x := range v {
+			sum[i] += float64(x) / norm
+		}
+	}
+	var norm float64
+	for _, x := range sum {
+		norm += x * x
+	}
+	out := make([]float32, len(sum))
+	if norm == 0 {
+		return out
+	}
+	norm = math.Sqrt(norm)
+	for i, x := range sum {
+		out[i] = float32(x / norm)
+	}
+	return out
+}
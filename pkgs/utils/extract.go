@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// Content types we can extract text from.
+const (
+	MIMEPDF      = "application/pdf"
+	MIMEDOCX     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMEODT      = "application/vnd.oasis.opendocument.text"
+	MIMERTF      = "application/rtf"
+	MIMEMarkdown = "text/markdown"
+	MIMEText     = "text/plain"
+)
+
+// ErrUnsupportedType is returned for files no extractor is registered for.
+var ErrUnsupportedType = errors.New("unsupported file type")
+
+// ExtractFunc pulls the plain text out of the file at path.
+type ExtractFunc func(path string) (string, error)
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   = map[string]TextExtractor{
+		MIMEPDF:      ExtractorChain{PDFExtractors["unipdf"], PDFExtractors["native"]},
+		MIMEDOCX:     NewTextExtractor("docx", ExtractTextFromDOCX),
+		MIMEODT:      NewTextExtractor("odt", ExtractTextFromODT),
+		MIMERTF:      NewTextExtractor("rtf", ExtractTextFromRTF),
+		MIMEMarkdown: NewTextExtractor("markdown", ExtractTextFromMarkdown),
+		MIMEText:     NewTextExtractor("text", ExtractTextFromPlain),
+	}
+)
+
+// RegisterExtractor adds or replaces the extractor for a content type.
+func RegisterExtractor(mime string, ex TextExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors[mime] = ex
+}
+
+// Extensions is the file extension we store each content type under.
+var Extensions = map[string]string{
+	MIMEPDF:      ".pdf",
+	MIMEDOCX:     ".docx",
+	MIMEODT:      ".odt",
+	MIMERTF:      ".rtf",
+	MIMEMarkdown: ".md",
+	MIMEText:     ".txt",
+}
+
+// DetectMIME sniffs the content type from the file's bytes. filename is only
+// consulted for formats that can't be told apart by content, such as Markdown
+// which sniffs as plain text.
+func DetectMIME(path, filename string) (string, error) {
+	m, err := mimetype.DetectFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot detect file type: %w", err)
+	}
+
+	mime := m.String()
+	if i := strings.Index(mime, ";"); i >= 0 {
+		mime = mime[:i] // drop "; charset=utf-8"
+	}
+	if mime == "text/rtf" {
+		mime = MIMERTF
+	}
+
+	if mime == MIMEText {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".md", ".markdown":
+			mime = MIMEMarkdown
+		}
+	}
+	return mime, nil
+}
+
+// ExtractText extracts text with the extractor registered for mime.
+func ExtractText(path, mime string) (string, error) {
+	extractorsMu.RLock()
+	ex, ok := extractors[mime]
+	extractorsMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, mime)
+	}
+	return ex.Extract(path)
+}
+
+// ExtractTextFromPlain reads a text file, normalising line endings.
+func ExtractTextFromPlain(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read text file: %w", err)
+	}
+	text := strings.TrimPrefix(string(b), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n"), nil
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoText is reported by a chain for an extractor that succeeded but found
+// no text, so the next one is tried.
+var ErrNoText = errors.New("no text extracted")
+
+// TextExtractor pulls the plain text out of the file at path.
+type TextExtractor interface {
+	Name() string
+	Extract(path string) (string, error)
+}
+
+type funcExtractor struct {
+	name string
+	fn   ExtractFunc
+}
+
+func (e funcExtractor) Name() string                        { return e.name }
+func (e funcExtractor) Extract(path string) (string, error) { return e.fn(path) }
+
+// NewTextExtractor wraps a plain function as a named TextExtractor.
+func NewTextExtractor(name string, fn ExtractFunc) TextExtractor {
+	return funcExtractor{name: name, fn: fn}
+}
+
+// ExtractorChain tries each extractor in turn and returns the first non-empty
+// result, falling back to the next one on an error or empty text.
+type ExtractorChain []TextExtractor
+
+func (c ExtractorChain) Name() string {
+	names := make([]string, len(c))
+	for i, ex := range c {
+		names[i] = ex.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+func (c ExtractorChain) Extract(path string) (string, error) {
+	var errs []error
+	for _, ex := range c {
+		text, err := ex.Extract(path)
+		if err == nil && strings.TrimSpace(text) != "" {
+			return text, nil
+		}
+		if err == nil {
+			err = ErrNoText
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", ex.Name(), err))
+	}
+	if len(errs) == 0 {
+		return "", ErrNoText
+	}
+	return "", errors.Join(errs...)
+}
+
+// PDF extractors selectable by name, see NewPDFExtractor.
+var PDFExtractors = map[string]TextExtractor{
+	"unipdf": NewTextExtractor("unipdf", ExtractTextFromPDF),
+	"native": NewTextExtractor("native", ExtractTextFromPDFNative),
+}
+
+// LayoutPDFExtractors are the same extractors in layout mode.
+var LayoutPDFExtractors = map[string]TextExtractor{
+	"unipdf": NewTextExtractor("unipdf-layout", ExtractLayoutFromPDF),
+	"native": NewTextExtractor("native-layout", ExtractLayoutFromPDFNative),
+}
+
+// DefaultPDFExtractors prefers unipdf and falls back to the license-free
+// extractor when no unidoc key is configured or unipdf fails.
+const DefaultPDFExtractors = "unipdf,native"
+
+// NewPDFExtractor builds a chain from a comma separated list of PDF extractor
+// names, in order of preference. mode is ExtractionPlain or ExtractionLayout.
+func NewPDFExtractor(names, mode string) (TextExtractor, error) {
+	available := PDFExtractors
+	switch mode {
+	case ExtractionPlain, "":
+	case ExtractionLayout:
+		available = LayoutPDFExtractors
+	default:
+		return nil, fmt.Errorf("unknown extraction mode %q", mode)
+	}
+
+	var chain ExtractorChain
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		ex, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown PDF extractor %q", name)
+		}
+		chain = append(chain, ex)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("no PDF extractor configured")
+	}
+	if len(chain) == 1 {
+		return chain[0], nil
+	}
+	return chain, nil
+}
@@ -0,0 +1,381 @@
+package utils
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Text extraction modes.
+const (
+	ExtractionPlain  = "plain"
+	ExtractionLayout = "layout"
+)
+
+// textMark is text drawn at a position on a page, in PDF points with y
+// growing upwards. Both PDF backends are reduced to marks so the layout
+// analysis below is shared.
+type textMark struct {
+	Text   string
+	X0, X1 float64
+	Y      float64 // baseline
+	Size   float64
+}
+
+// segment is a run of marks on one line without a wide horizontal gap.
+type segment struct {
+	Text   string
+	X0, X1 float64
+}
+
+type textLine struct {
+	Y    float64
+	Size float64
+	Segs []segment
+}
+
+func (l textLine) text() string {
+	parts := make([]string, len(l.Segs))
+	for i, s := range l.Segs {
+		parts[i] = s.Text
+	}
+	return strings.Join(parts, " ")
+}
+
+func (l textLine) x0() float64 { return l.Segs[0].X0 }
+
+// groupLines builds lines from marks, top to bottom, splitting each line into
+// segments wherever the gap is wider than a font size: column gutters and
+// table cells. Whitespace marks are not kept but separate words, as some
+// fonts report glyphs without a width and leave no gap to go by.
+func groupLines(marks []textMark) []textLine {
+	sorted := append(marks[:0:0], marks...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Y > sorted[j].Y })
+
+	var lines []textLine
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && onSameLine(sorted[start], sorted[end]) {
+			end++
+		}
+		row := sorted[start:end]
+		start = end
+		sort.SliceStable(row, func(i, j int) bool { return row[i].X0 < row[j].X0 })
+
+		var line textLine
+		var cur *segment
+		var text strings.Builder
+		var prev *textMark
+		space := false
+		for i := range row {
+			m := &row[i]
+			if strings.TrimSpace(m.Text) == "" {
+				space = true
+				continue
+			}
+			if prev == nil {
+				line.Y = m.Y
+			} else {
+				gap := m.X0 - prev.X1
+				switch {
+				case gap > fontSize(*m):
+					cur.Text = strings.TrimSpace(text.String())
+					line.Segs = append(line.Segs, *cur)
+					cur = nil
+				case space || gap > fontSize(*m)*0.2:
+					text.WriteString(" ") // a visible gap may have no space glyph
+				}
+			}
+			if cur == nil {
+				cur = &segment{X0: m.X0}
+				text.Reset()
+			}
+			line.Size = math.Max(line.Size, m.Size)
+			text.WriteString(m.Text)
+			cur.X1 = m.X1
+			prev, space = m, false
+		}
+		if cur == nil {
+			continue // only whitespace
+		}
+		cur.Text = strings.TrimSpace(text.String())
+		line.Segs = append(line.Segs, *cur)
+
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func fontSize(m textMark) float64 {
+	if m.Size <= 0 {
+		return 1
+	}
+	return m.Size
+}
+
+// onSameLine treats marks whose baselines are within half a font size as one
+// line, which absorbs sub- and superscripts.
+func onSameLine(a, b textMark) bool {
+	return math.Abs(a.Y-b.Y) < math.Max(fontSize(a), fontSize(b))/2
+}
+
+// plainPage joins the lines of a page without any structure.
+func plainPage(marks []textMark) string {
+	var out strings.Builder
+	for _, l := range groupLines(marks) {
+		out.WriteString(l.text())
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// layoutPage rebuilds reading order across columns and renders headings,
+// bullet lists and simple tables as Markdown.
+func layoutPage(marks []textMark) string {
+	lines := groupLines(marks)
+	if len(lines) == 0 {
+		return ""
+	}
+
+	body := medianSize(lines)
+	var out strings.Builder
+	for i, block := range readingOrder(lines) {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		renderBlock(&out, block, body)
+	}
+	return out.String()
+}
+
+// readingOrder splits lines into blocks in the order they should be read. On
+// a two column page the left column is read before the right one; lines that
+// span the gutter, such as a centred name, start a new band of columns.
+func readingOrder(lines []textLine) [][]textLine {
+	gutter, ok := findGutter(lines)
+	if !ok {
+		return [][]textLine{lines}
+	}
+
+	var blocks [][]textLine
+	var left, right []textLine
+	flush := func() {
+		if len(left) > 0 {
+			blocks = append(blocks, left)
+		}
+		if len(right) > 0 {
+			blocks = append(blocks, right)
+		}
+		left, right = nil, nil
+	}
+
+	for _, l := range lines {
+		var ls, rs []segment
+		spans := false
+		for _, s := range l.Segs {
+			switch {
+			case s.X1 <= gutter:
+				ls = append(ls, s)
+			case s.X0 >= gutter:
+				rs = append(rs, s)
+			default:
+				spans = true
+			}
+		}
+		if spans {
+			flush()
+			blocks = append(blocks, []textLine{l})
+			continue
+		}
+		if len(ls) > 0 {
+			left = append(left, textLine{Y: l.Y, Size: l.Size, Segs: ls})
+		}
+		if len(rs) > 0 {
+			right = append(right, textLine{Y: l.Y, Size: l.Size, Segs: rs})
+		}
+	}
+	flush()
+	return blocks
+}
+
+// findGutter looks for a vertical strip in the middle of the page that almost
+// no segment crosses while enough lines sit on both sides of it, and returns
+// its centre.
+func findGutter(lines []textLine) (float64, bool) {
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, l := range lines {
+		for _, s := range l.Segs {
+			minX = math.Min(minX, s.X0)
+			maxX = math.Max(maxX, s.X1)
+		}
+	}
+	width := maxX - minX
+	if width < 100 || len(lines) < 6 {
+		return 0, false
+	}
+
+	// coverage of each 1pt column by segments
+	bins := make([]int, int(width)+1)
+	for _, l := range lines {
+		for _, s := range l.Segs {
+			for x := int(s.X0 - minX); x <= int(s.X1-minX) && x < len(bins); x++ {
+				bins[x]++
+			}
+		}
+	}
+
+	allowed := len(lines) / 10 // headers and rules may cross the gutter
+	lo, hi := int(width*0.2), int(width*0.8)
+	bestStart, bestLen := 0, 0
+	for x := lo; x <= hi; {
+		if bins[x] > allowed {
+			x++
+			continue
+		}
+		start := x
+		for x <= hi && bins[x] <= allowed {
+			x++
+		}
+		if x-start > bestLen {
+			bestStart, bestLen = start, x-start
+		}
+	}
+	if bestLen < 12 {
+		return 0, false
+	}
+
+	gutter := minX + float64(bestStart) + float64(bestLen)/2
+	var leftLines, rightLines int
+	for _, l := range lines {
+		if l.Segs[0].X1 <= gutter {
+			leftLines++
+		}
+		if l.Segs[len(l.Segs)-1].X0 >= gutter {
+			rightLines++
+		}
+	}
+	if leftLines < 3 || rightLines < 3 {
+		return 0, false
+	}
+	return gutter, true
+}
+
+func medianSize(lines []textLine) float64 {
+	sizes := make([]float64, len(lines))
+	for i, l := range lines {
+		sizes[i] = l.Size
+	}
+	sort.Float64s(sizes)
+	return sizes[len(sizes)/2]
+}
+
+// bullet glyphs, including the Symbol and Wingdings code points Word uses
+var bulletGlyphs = []string{"•", "●", "▪", "■", "◦", "‣", "∙", "·", "–", "-", "*", "\uf0b7", "\uf0a7", "➢", "✓", "✔"}
+
+// bulletText returns the text after a leading bullet glyph.
+func bulletText(s string) (string, bool) {
+	for _, b := range bulletGlyphs {
+		if rest, ok := strings.CutPrefix(s, b); ok {
+			rest = strings.TrimSpace(rest)
+			if rest != "" && (b != "-" && b != "*" || !unicode.IsDigit([]rune(rest)[0])) {
+				return rest, true
+			}
+		}
+	}
+	return "", false
+}
+
+func renderBlock(out *strings.Builder, block []textLine, body float64) {
+	var rendered []string
+	inBullet := false
+	var bulletX float64
+	for i := 0; i < len(block); i++ {
+		l := block[i]
+
+		if n := tableRows(block[i:]); n > 0 {
+			rendered = append(rendered, renderTable(block[i:i+n])...)
+			i += n - 1
+			inBullet = false
+			continue
+		}
+
+		if i > 0 {
+			prev := block[i-1]
+			if prev.Y-l.Y > 1.8*math.Max(prev.Size, l.Size) {
+				rendered = append(rendered, "") // paragraph break
+				inBullet = false
+			}
+		}
+
+		text := l.text()
+		if rest, ok := bulletText(text); ok {
+			rendered = append(rendered, "- "+rest)
+			inBullet, bulletX = true, l.x0()
+			continue
+		}
+		if inBullet && l.x0() > bulletX+1 {
+			// wrapped bullet text is indented past the bullet glyph
+			rendered[len(rendered)-1] += " " + text
+			continue
+		}
+		inBullet = false
+
+		if l.Size >= body*1.2 && len([]rune(text)) <= 80 {
+			rendered = append(rendered, "## "+text)
+			continue
+		}
+		rendered = append(rendered, text)
+	}
+
+	for _, r := range rendered {
+		out.WriteString(r)
+		out.WriteString("\n")
+	}
+}
+
+// tableRows counts the rows of a table starting at lines[0]: at least two
+// consecutive lines with the same number (2+) of cells starting at the same
+// x positions.
+func tableRows(lines []textLine) int {
+	first := lines[0]
+	if len(first.Segs) < 2 {
+		return 0
+	}
+	n := 1
+	for n < len(lines) && alignedCells(first, lines[n]) {
+		n++
+	}
+	if n < 2 {
+		return 0
+	}
+	return n
+}
+
+func alignedCells(a, b textLine) bool {
+	if len(a.Segs) != len(b.Segs) {
+		return false
+	}
+	tolerance := math.Max(a.Size, b.Size) * 1.5
+	for i := range a.Segs {
+		if math.Abs(a.Segs[i].X0-b.Segs[i].X0) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func renderTable(rows []textLine) []string {
+	var rendered []string
+	for i, r := range rows {
+		cells := make([]string, len(r.Segs))
+		for j, s := range r.Segs {
+			cells[j] = strings.ReplaceAll(s.Text, "|", "\\|")
+		}
+		rendered = append(rendered, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			rendered = append(rendered, "|"+strings.Repeat(" --- |", len(cells)))
+		}
+	}
+	return rendered
+}
@@ -0,0 +1,227 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// words lays out text as one mark per word starting at x on baseline y, with
+// glyphs half a font size wide and a small gap standing in for the spaces.
+func words(x, y, size float64, text string) []textMark {
+	var marks []textMark
+	for _, w := range strings.Fields(text) {
+		width := float64(len([]rune(w))) * size / 2
+		marks = append(marks, textMark{Text: w, X0: x, X1: x + width, Y: y, Size: size})
+		x += width + size*0.3
+	}
+	return marks
+}
+
+// row lays out cells at the given x positions on one baseline.
+func row(y float64, cells map[float64]string) []textMark {
+	var marks []textMark
+	for x, text := range cells {
+		marks = append(marks, words(x, y, 10, text)...)
+	}
+	return marks
+}
+
+func TestGroupLines(t *testing.T) {
+	marks := append(words(50, 700, 10, "Senior Go Engineer"), words(300, 700, 10, "2019 2023")...)
+	// a subscript a little below the baseline stays on the line
+	marks = append(marks, textMark{Text: "x", X0: 50, X1: 55, Y: 687, Size: 10})
+	marks = append(marks, words(60, 686, 10, "Jakarta")...)
+
+	lines := groupLines(marks)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	var segs []string
+	for _, s := range lines[0].Segs {
+		segs = append(segs, s.Text)
+	}
+	if got := strings.Join(segs, "|"); got != "Senior Go Engineer|2019 2023" {
+		t.Errorf("segments = %q", got)
+	}
+	if got := lines[1].text(); got != "x Jakarta" {
+		t.Errorf("second line = %q", got)
+	}
+}
+
+// glyphs lays out text one mark per character, spaces included, the way the
+// native extractor reports standard fonts: every glyph without a width.
+func glyphs(x, y, size float64, text string) []textMark {
+	var marks []textMark
+	for _, r := range text {
+		marks = append(marks, textMark{Text: string(r), X0: x, X1: x, Y: y, Size: size})
+	}
+	return marks
+}
+
+func TestPlainPage(t *testing.T) {
+	tests := []struct {
+		name  string
+		marks []textMark
+		want  string
+	}{
+		{
+			name:  "empty page",
+			marks: nil,
+			want:  "",
+		},
+		{
+			name:  "words with gaps",
+			marks: append(words(50, 700, 16, "John Doe"), words(50, 680, 10, "Software Engineer")...),
+			want:  "John Doe\nSoftware Engineer\n",
+		},
+		{
+			name: "glyphs without width are split at space glyphs",
+			marks: append(append(glyphs(50, 700, 16, "John Doe"), glyphs(50, 680, 10, "Software Engineer")...),
+				glyphs(50, 660, 10, "Go, PostgreSQL")...),
+			want: "John Doe\nSoftware Engineer\nGo, PostgreSQL\n",
+		},
+		{
+			name:  "whitespace only line is dropped",
+			marks: append(glyphs(50, 700, 10, "Skills"), glyphs(50, 680, 10, "   ")...),
+			want:  "Skills\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plainPage(tt.marks); got != tt.want {
+				t.Errorf("plainPage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulletText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{in: "• Built REST APIs", want: "Built REST APIs", ok: true},
+		{in: "\uf0b7 Led a team of 4", want: "Led a team of 4", ok: true},
+		{in: "- Mentored juniors", want: "Mentored juniors", ok: true},
+		{in: "-2019 to 2021", ok: false},
+		{in: "* 5 years of Go", ok: false},
+		{in: "•", ok: false},
+		{in: "Plain text", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := bulletText(tt.in)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("bulletText(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLayoutPage(t *testing.T) {
+	var twoColumns []textMark
+	twoColumns = append(twoColumns, words(170, 800, 16, "Jane Doe")...)
+	for i := 0; i < 10; i++ {
+		y := 770 - float64(i)*14
+		twoColumns = append(twoColumns, words(50, y, 10, "Left "+string(rune('A'+i)))...)
+		twoColumns = append(twoColumns, words(300, y, 10, "Right "+string(rune('A'+i)))...)
+	}
+
+	var table []textMark
+	table = append(table, row(700, map[float64]string{50: "Skill", 200: "Level", 350: "Years"})...)
+	table = append(table, row(686, map[float64]string{50: "Go", 200: "Expert", 350: "5"})...)
+	table = append(table, row(672, map[float64]string{50: "SQL", 200: "Good", 350: "3"})...)
+	table = append(table, words(50, 640, 10, "After the table")...)
+
+	var bullets []textMark
+	bullets = append(bullets, words(50, 700, 14, "Experience")...)
+	bullets = append(bullets, words(50, 680, 10, "• Built payment APIs")...)
+	bullets = append(bullets, words(60, 668, 10, "and reporting jobs")...)
+	bullets = append(bullets, words(50, 656, 10, "• Led a team")...)
+	bullets = append(bullets, words(50, 620, 10, "Education")...)
+
+	tests := []struct {
+		name  string
+		marks []textMark
+		want  string
+	}{
+		{
+			name:  "empty page",
+			marks: nil,
+			want:  "",
+		},
+		{
+			name:  "left column is read before the right one",
+			marks: twoColumns,
+			want: "## Jane Doe\n\n" +
+				"Left A\nLeft B\nLeft C\nLeft D\nLeft E\nLeft F\nLeft G\nLeft H\nLeft I\nLeft J\n\n" +
+				"Right A\nRight B\nRight C\nRight D\nRight E\nRight F\nRight G\nRight H\nRight I\nRight J\n",
+		},
+		{
+			name:  "aligned cells become a table",
+			marks: table,
+			want: "| Skill | Level | Years |\n| --- | --- | --- |\n| Go | Expert | 5 |\n| SQL | Good | 3 |\n" +
+				"\nAfter the table\n",
+		},
+		{
+			name:  "headings and wrapped bullets",
+			marks: bullets,
+			want:  "## Experience\n- Built payment APIs and reporting jobs\n- Led a team\n\nEducation\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := layoutPage(tt.marks); got != tt.want {
+				t.Errorf("layoutPage() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindGutter(t *testing.T) {
+	tests := []struct {
+		name  string
+		marks func() []textMark
+		ok    bool
+	}{
+		{
+			name: "two columns",
+			marks: func() []textMark {
+				var m []textMark
+				for i := 0; i < 8; i++ {
+					m = append(m, words(50, 700-float64(i)*14, 10, "Left")...)
+					m = append(m, words(300, 700-float64(i)*14, 10, "Right")...)
+				}
+				return m
+			},
+			ok: true,
+		},
+		{
+			name: "single column of full lines",
+			marks: func() []textMark {
+				var m []textMark
+				for i := 0; i < 8; i++ {
+					m = append(m, words(50, 700-float64(i)*14, 10, "a long sentence that runs across the whole page width")...)
+				}
+				return m
+			},
+			ok: false,
+		},
+		{
+			name: "too few lines",
+			marks: func() []textMark {
+				return append(words(50, 700, 10, "Left"), words(300, 700, 10, "Right")...)
+			},
+			ok: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := findGutter(groupLines(tt.marks()))
+			if ok != tt.ok {
+				t.Errorf("findGutter ok = %v, want %v", ok, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// ExtractLinks returns the hyperlink targets embedded in a document, which
+// text extraction drops: URI link annotations in PDFs and external
+// hyperlinks in DOCX and ODT. Other formats carry their links in the text.
+func ExtractLinks(path, mime string) ([]string, error) {
+	switch mime {
+	case MIMEPDF:
+		return pdfLinks(path)
+	case MIMEDOCX:
+		return zipLinks(path, "word/_rels/document.xml.rels", docxLink)
+	case MIMEODT:
+		return zipLinks(path, "content.xml", odtLink)
+	}
+	return nil, nil
+}
+
+func pdfLinks(path string) (links []string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat PDF file: %w", err)
+	}
+
+	reader, err := pdf.NewReader(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PDF reader: %w", err)
+	}
+
+	// value lookups panic on malformed objects
+	defer func() {
+		if r := recover(); r != nil {
+			links, err = nil, fmt.Errorf("malformed PDF annotations: %v", r)
+		}
+	}()
+
+	for i := 1; i <= reader.NumPage(); i++ {
+		annots := reader.Page(i).V.Key("Annots")
+		for j := 0; j < annots.Len(); j++ {
+			annot := annots.Index(j)
+			if annot.Key("Subtype").Name() != "Link" {
+				continue
+			}
+			action := annot.Key("A")
+			if action.Key("S").Name() != "URI" {
+				continue
+			}
+			if uri := strings.TrimSpace(action.Key("URI").RawString()); uri != "" {
+				links = append(links, uri)
+			}
+		}
+	}
+	return links, nil
+}
+
+// docxLink returns the target of an external hyperlink relationship.
+func docxLink(el xml.StartElement) string {
+	if el.Name.Local != "Relationship" {
+		return ""
+	}
+	var target, mode, kind string
+	for _, a := range el.Attr {
+		switch a.Name.Local {
+		case "Target":
+			target = a.Value
+		case "TargetMode":
+			mode = a.Value
+		case "Type":
+			kind = a.Value
+		}
+	}
+	if mode != "External" || !strings.HasSuffix(kind, "/hyperlink") {
+		return ""
+	}
+	return target
+}
+
+// odtLink returns the href of a text:a element.
+func odtLink(el xml.StartElement) string {
+	if el.Name.Local != "a" {
+		return ""
+	}
+	for _, a := range el.Attr {
+		if a.Name.Local == "href" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func zipLinks(path, name string, link func(xml.StartElement) string) ([]string, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open document: %w", err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		var links []string
+		dec := xml.NewDecoder(rc)
+		for {
+			tok, err := dec.Token()
+			if err == io.EOF {
+				return links, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			if el, ok := tok.(xml.StartElement); ok {
+				if l := link(el); l != "" {
+					links = append(links, l)
+				}
+			}
+		}
+	}
+	return nil, nil // no hyperlinks part
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	mdImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	mdAutoLink   = regexp.MustCompile(`<((?:https?://|mailto:)[^>]+)>`)
+	mdBold       = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdItalic     = regexp.MustCompile(`(^|[\s(])[*_]([^*_\s][^*_]*?)[*_]([\s).,;:!?]|$)`)
+	mdStrike     = regexp.MustCompile(`~~(.+?)~~`)
+	mdCode       = regexp.MustCompile("`([^`]+)`")
+	mdHTML       = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	mdHeading    = regexp.MustCompile(`^\s{0,3}#{1,6}\s+`)
+	mdBullet     = regexp.MustCompile(`^(\s*)[*+]\s+`)
+	mdQuote      = regexp.MustCompile(`^\s{0,3}>\s?`)
+	mdRule       = regexp.MustCompile(`^\s{0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdTableBreak = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+)
+
+// ExtractTextFromMarkdown reads a Markdown file and strips the markup, keeping
+// link targets since CVs use them for profiles and portfolios.
+func ExtractTextFromMarkdown(path string) (string, error) {
+	text, err := ExtractTextFromPlain(path)
+	if err != nil {
+		return "", err
+	}
+	return markdownToText(text), nil
+}
+
+func markdownToText(md string) string {
+	lines := strings.Split(md, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			continue
+		}
+		if mdRule.MatchString(line) || mdTableBreak.MatchString(line) {
+			out = append(out, "")
+			continue
+		}
+
+		line = mdHeading.ReplaceAllString(line, "")
+		line = mdQuote.ReplaceAllString(line, "")
+		line = mdBullet.ReplaceAllString(line, "$1- ")
+		line = mdImage.ReplaceAllString(line, "$1")
+		line = mdLink.ReplaceAllString(line, "$1 ($2)")
+		line = mdAutoLink.ReplaceAllString(line, "$1")
+		line = mdBold.ReplaceAllString(line, "$2")
+		line = mdItalic.ReplaceAllString(line, "$1$2$3")
+		line = mdStrike.ReplaceAllString(line, "$1")
+		line = mdCode.ReplaceAllString(line, "$1")
+		line = mdHTML.ReplaceAllString(line, "")
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// RunOCR runs a local OCR command on the file at path and returns what it
+// prints on stdout. The command is split on whitespace; a "{file}" argument
+// is replaced by path, otherwise path is appended, e.g.
+//
+//	ocr-cv.sh {file}
+func RunOCR(ctx context.Context, command, path string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errors.New("no OCR command configured")
+	}
+
+	replaced := false
+	for i, a := range args[1:] {
+		if strings.Contains(a, "{file}") {
+			args[i+1] = strings.ReplaceAll(a, "{file}", path)
+			replaced = true
+		}
+	}
+	if !replaced {
+		args = append(args, path)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("ocr command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
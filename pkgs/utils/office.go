@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExtractTextFromDOCX reads the body, headers and footers of a Word document.
+// Headers often hold the candidate's contact details, so they come first.
+func ExtractTextFromDOCX(filePath string) (string, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open DOCX: %w", err)
+	}
+	defer zr.Close()
+
+	var headers, footers []*zip.File
+	var body *zip.File
+	for _, f := range zr.File {
+		name := path.Base(f.Name)
+		switch {
+		case f.Name == "word/document.xml":
+			body = f
+		case strings.HasPrefix(f.Name, "word/header") && strings.HasSuffix(name, ".xml"):
+			headers = append(headers, f)
+		case strings.HasPrefix(f.Name, "word/footer") && strings.HasSuffix(name, ".xml"):
+			footers = append(footers, f)
+		}
+	}
+	if body == nil {
+		return "", fmt.Errorf("failed to read DOCX: word/document.xml missing")
+	}
+	byName := func(fs []*zip.File) {
+		sort.Slice(fs, func(i, j int) bool { return fs[i].Name < fs[j].Name })
+	}
+	byName(headers)
+	byName(footers)
+
+	var sb strings.Builder
+	for _, f := range append(append(headers, body), footers...) {
+		if err := readZipXML(f, &sb, docxHandler); err != nil {
+			return "", fmt.Errorf("failed to read DOCX %s: %w", f.Name, err)
+		}
+	}
+	return sb.String(), nil
+}
+
+// ExtractTextFromODT reads the body of an OpenDocument text file.
+func ExtractTextFromODT(filePath string) (string, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open ODT: %w", err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.Name != "content.xml" {
+			continue
+		}
+		var sb strings.Builder
+		if err := readZipXML(f, &sb, odtHandler); err != nil {
+			return "", fmt.Errorf("failed to read ODT: %w", err)
+		}
+		return sb.String(), nil
+	}
+	return "", fmt.Errorf("failed to read ODT: content.xml missing")
+}
+
+// xmlHandler turns one XML token into text. inText tracks whether character
+// data at this point is document text.
+type xmlHandler func(tok xml.Token, sb *strings.Builder, inText *int)
+
+func readZipXML(f *zip.File, sb *strings.Builder, handle xmlHandler) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	dec := xml.NewDecoder(rc)
+	inText := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		handle(tok, sb, &inText)
+	}
+}
+
+func docxHandler(tok xml.Token, sb *strings.Builder, inText *int) {
+	switch t := tok.(type) {
+	case xml.StartElement:
+		switch t.Name.Local {
+		case "t":
+			*inText++
+		case "tab":
+			sb.WriteString("\t")
+		case "br", "cr":
+			sb.WriteString("\n")
+		}
+	case xml.EndElement:
+		switch t.Name.Local {
+		case "t":
+			*inText--
+		case "p":
+			sb.WriteString("\n")
+		case "tc":
+			sb.WriteString("\t")
+		}
+	case xml.CharData:
+		if *inText > 0 {
+			sb.Write(t)
+		}
+	}
+}
+
+func odtHandler(tok xml.Token, sb *strings.Builder, inText *int) {
+	switch t := tok.(type) {
+	case xml.StartElement:
+		switch t.Name.Local {
+		case "p", "h":
+			*inText++
+		case "tab":
+			sb.WriteString("\t")
+		case "line-break":
+			sb.WriteString("\n")
+		case "s":
+			n := 1
+			for _, a := range t.Attr {
+				if a.Name.Local == "c" {
+					if c, err := strconv.Atoi(a.Value); err == nil {
+						n = c
+					}
+				}
+			}
+			sb.WriteString(strings.Repeat(" ", n))
+		}
+	case xml.EndElement:
+		switch t.Name.Local {
+		case "p", "h":
+			*inText--
+			sb.WriteString("\n")
+		case "table-cell":
+			sb.WriteString("\t")
+		}
+	case xml.CharData:
+		if *inText > 0 {
+			sb.Write(t)
+		}
+	}
+}
@@ -1,9 +1,7 @@
 package utils
 
 import (
-	"encoding/binary"
 	"fmt"
-	"math"
 	"os"
 	"strings"
 
@@ -11,74 +9,98 @@ import (
 	"github.com/unidoc/unipdf/v4/model"
 )
 
-// ExtractTextFromPDF loads a document using the klippa-app/go-pdfium library
-// and extracts all text content by iterating through pages.
+// ExtractTextFromPDF loads a document using unipdf and extracts all text
+// content by iterating through pages. It needs a metered unidoc license.
 func ExtractTextFromPDF(path string) (string, error) {
+	var allText strings.Builder
+	err := eachUniPDFPage(path, func(i int, ex *extractor.Extractor) error {
+		pageText, err := ex.ExtractText()
+		if err != nil {
+			return fmt.Errorf("failed to extract text from page %d: %w", i, err)
+		}
+
+		allText.WriteString(pageText)
+		allText.WriteString(PageBreak) // separate pages
+		return nil
+	})
+	return allText.String(), err
+}
+
+// ExtractLayoutFromPDF is ExtractTextFromPDF in layout mode: reading order is
+// rebuilt from the positions of the text marks, see layoutPage.
+func ExtractLayoutFromPDF(path string) (string, error) {
+	var allText strings.Builder
+	err := eachUniPDFPage(path, func(i int, ex *extractor.Extractor) error {
+		pageText, _, _, err := ex.ExtractPageText()
+		if err != nil {
+			return fmt.Errorf("failed to extract text from page %d: %w", i, err)
+		}
+
+		var marks []textMark
+		for _, m := range pageText.Marks().Elements() {
+			if m.Meta {
+				continue // spaces and line breaks unipdf inserted
+			}
+			marks = append(marks, textMark{
+				Text: m.Text,
+				X0:   m.BBox.Llx,
+				X1:   m.BBox.Urx,
+				Y:    m.BBox.Lly,
+				Size: m.FontSize,
+			})
+		}
+
+		allText.WriteString(layoutPage(marks))
+		allText.WriteString(PageBreak) // separate pages
+		return nil
+	})
+	return allText.String(), err
+}
+
+// eachUniPDFPage opens the PDF at path and calls fn with a text extractor for
+// every page in order.
+func eachUniPDFPage(path string, fn func(i int, ex *extractor.Extractor) error) error {
 	// --- 1. Open the PDF file ---
 	file, err := os.Open(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to open PDF file: %w", err)
+		return fmt.Errorf("failed to open PDF file: %w", err)
 	}
 	defer file.Close()
 
 	// --- 2. Read the PDF into a UniPDF Reader ---
 	pdfReader, err := model.NewPdfReader(file)
 	if err != nil {
-		return "", fmt.Errorf("failed to create PDF reader: %w", err)
+		return fmt.Errorf("failed to create PDF reader: %w", err)
+	}
+
+	// owner-password-only PDFs still need decrypting before reading
+	if encrypted, err := pdfReader.IsEncrypted(); err == nil && encrypted {
+		if ok, err := pdfReader.Decrypt([]byte("")); err != nil || !ok {
+			return fmt.Errorf("PDF is password protected")
+		}
 	}
 
 	// --- 3. Get number of pages ---
 	numPages, err := pdfReader.GetNumPages()
 	if err != nil {
-		return "", fmt.Errorf("failed to get number of pages: %w", err)
+		return fmt.Errorf("failed to get number of pages: %w", err)
 	}
 
-	var allText strings.Builder
-
 	// --- 4. Iterate through pages ---
 	for i := 1; i <= numPages; i++ {
 		page, err := pdfReader.GetPage(i)
 		if err != nil {
-			return "", fmt.Errorf("failed to get page %d: %w", i, err)
+			return fmt.Errorf("failed to get page %d: %w", i, err)
 		}
 
 		// --- 5. Extract text from the page ---
 		ex, err := extractor.New(page)
 		if err != nil {
-			return "", fmt.Errorf("failed to create text extractor for page %d: %w", i, err)
+			return fmt.Errorf("failed to create text extractor for page %d: %w", i, err)
 		}
-
-		pageText, err := ex.ExtractText()
-		if err != nil {
-			return "", fmt.Errorf("failed to extract text from page %d: %w", i, err)
+		if err := fn(i, ex); err != nil {
+			return err
 		}
-
-		allText.WriteString(pageText)
-		allText.WriteString("\n\n") // separate pages
 	}
-
-	return allText.String(), nil
-}
-
-// BytesToFloat32sBinary converts a []byte slice to a []float32 slice
-// using the encoding/binary package.
-// It assumes Little Endian byte order. Use binary.BigEndian for Big Endian.
-func BytesToFloat32sBinary(b []byte) ([]float32, error) {
-	if len(b)%4 != 0 {
-		return nil, fmt.Errorf("byte slice length must be a multiple of 4, got %d", len(b))
-	}
-
-	floats := make([]float32, 0, len(b)/4)
-
-	// Iterate over the byte slice 4 bytes at a time
-	for i := 0; i < len(b); i += 4 {
-		// Read the 4 bytes as a uint32
-		bits := binary.LittleEndian.Uint32(b[i : i+4])
-
-		// Convert the uint32 bit pattern to a float32
-		f := math.Float32frombits(bits)
-		floats = append(floats, f)
-	}
-
-	return floats, nil
+	return nil
 }
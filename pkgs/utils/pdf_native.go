@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// ExtractTextFromPDFNative extracts text with a pure Go PDF reader. It needs
+// no license key, but handles fewer font encodings than unipdf.
+func ExtractTextFromPDFNative(path string) (string, error) {
+	return extractNative(path, plainPage)
+}
+
+// ExtractLayoutFromPDFNative is ExtractTextFromPDFNative in layout mode, see
+// layoutPage.
+func ExtractLayoutFromPDFNative(path string) (string, error) {
+	return extractNative(path, layoutPage)
+}
+
+func extractNative(path string, render func([]textMark) string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open PDF file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat PDF file: %w", err)
+	}
+
+	reader, err := pdf.NewReader(f, info.Size())
+	if err != nil {
+		return "", fmt.Errorf("failed to create PDF reader: %w", err)
+	}
+
+	var allText strings.Builder
+	for i := 1; i <= reader.NumPage(); i++ {
+		marks, err := nativeMarks(reader.Page(i))
+		if err != nil {
+			return "", fmt.Errorf("failed to extract text from page %d: %w", i, err)
+		}
+		allText.WriteString(render(marks))
+		allText.WriteString(PageBreak) // separate pages
+	}
+
+	return allText.String(), nil
+}
+
+// nativeMarks returns the positioned glyphs of a page.
+func nativeMarks(page pdf.Page) (marks []textMark, err error) {
+	if page.V.IsNull() {
+		return nil, nil
+	}
+
+	// the content interpreter panics on malformed streams
+	defer func() {
+		if r := recover(); r != nil {
+			marks, err = nil, fmt.Errorf("malformed page content: %v", r)
+		}
+	}()
+
+	for _, g := range page.Content().Text {
+		marks = append(marks, textMark{
+			Text: g.S,
+			X0:   g.X,
+			X1:   g.X + g.W,
+			Y:    g.Y,
+			Size: g.FontSize,
+		})
+	}
+	return marks, nil
+}
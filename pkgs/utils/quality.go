@@ -0,0 +1,110 @@
+package utils
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// PageBreak separates pages in extracted PDF text.
+const PageBreak = "\f"
+
+//go:embed words.txt
+var wordList string
+
+var dictionary = func() map[string]struct{} {
+	words := map[string]struct{}{}
+	for _, line := range strings.Split(wordList, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words[line] = struct{}{}
+	}
+	return words
+}()
+
+// Quality describes how usable extracted text is. Scanned, image-only
+// documents come out with few characters, many empty pages and, when a broken
+// font map is involved, unprintable runes and no recognisable words.
+type Quality struct {
+	Pages           int     `json:"pages"`
+	Chars           int     `json:"chars"`
+	CharsPerPage    float64 `json:"chars_per_page"`
+	PrintableRatio  float64 `json:"printable_ratio"`
+	DictionaryRatio float64 `json:"dictionary_ratio"`
+	EmptyPageRatio  float64 `json:"empty_page_ratio"`
+	OCR             bool    `json:"ocr,omitempty"` // text came from the OCR hook
+}
+
+// QualityThresholds is the minimum quality we evaluate; zero disables a check.
+type QualityThresholds struct {
+	MinCharsPerPage    float64
+	MinPrintableRatio  float64
+	MinDictionaryRatio float64
+	MaxEmptyPageRatio  float64
+}
+
+// MeasureQuality computes quality metrics for text, with pages separated by
+// PageBreak. Text without page breaks counts as a single page.
+func MeasureQuality(text string) Quality {
+	pages := strings.Split(strings.TrimSuffix(text, PageBreak), PageBreak)
+
+	q := Quality{Pages: len(pages)}
+	var printable, empty, words, known int
+	for _, page := range pages {
+		pageChars := 0
+		for _, r := range page {
+			if unicode.IsSpace(r) {
+				continue
+			}
+			pageChars++
+			if unicode.IsPrint(r) && r != unicode.ReplacementChar && !unicode.Is(unicode.Co, r) {
+				printable++
+			}
+		}
+		if pageChars == 0 {
+			empty++
+		}
+		q.Chars += pageChars
+
+		for _, w := range strings.FieldsFunc(strings.ToLower(page), func(r rune) bool { return !unicode.IsLetter(r) }) {
+			if len([]rune(w)) < 2 && w != "a" && w != "i" {
+				continue
+			}
+			words++
+			if _, ok := dictionary[w]; ok {
+				known++
+			}
+		}
+	}
+
+	q.CharsPerPage = float64(q.Chars) / float64(q.Pages)
+	q.EmptyPageRatio = float64(empty) / float64(q.Pages)
+	if q.Chars > 0 {
+		q.PrintableRatio = float64(printable) / float64(q.Chars)
+	}
+	if words > 0 {
+		q.DictionaryRatio = float64(known) / float64(words)
+	}
+	return q
+}
+
+// Problems lists the thresholds q falls short of; empty when it is usable.
+func (q Quality) Problems(t QualityThresholds) []string {
+	var problems []string
+	if t.MinCharsPerPage > 0 && q.CharsPerPage < t.MinCharsPerPage {
+		problems = append(problems, fmt.Sprintf("%.0f characters per page, need %.0f", q.CharsPerPage, t.MinCharsPerPage))
+	}
+	if t.MinPrintableRatio > 0 && q.PrintableRatio < t.MinPrintableRatio {
+		problems = append(problems, fmt.Sprintf("%.0f%% printable characters, need %.0f%%", q.PrintableRatio*100, t.MinPrintableRatio*100))
+	}
+	if t.MinDictionaryRatio > 0 && q.DictionaryRatio < t.MinDictionaryRatio {
+		problems = append(problems, fmt.Sprintf("%.0f%% dictionary words, need %.0f%%", q.DictionaryRatio*100, t.MinDictionaryRatio*100))
+	}
+	if t.MaxEmptyPageRatio > 0 && q.EmptyPageRatio > t.MaxEmptyPageRatio {
+		problems = append(problems, fmt.Sprintf("%.0f%% of pages have no text, allowed %.0f%%", q.EmptyPageRatio*100, t.MaxEmptyPageRatio*100))
+	}
+	return problems
+}
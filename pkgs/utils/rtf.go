@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// rtfSkipDestinations hold metadata, fonts, pictures and the like rather than
+// document text.
+var rtfSkipDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+	"pict": true, "object": true, "themedata": true, "colorschememapping": true,
+	"latentstyles": true, "datastore": true, "xmlnstbl": true, "listtable": true,
+	"listoverridetable": true, "rsidtbl": true, "generator": true, "fldinst": true,
+	"filetbl": true, "revtbl": true, "pgdsctbl": true, "mmathPr": true,
+}
+
+var rtfSymbols = map[string]string{
+	"par": "\n", "line": "\n", "sect": "\n", "page": "\n", "row": "\n",
+	"tab": "\t", "cell": "\t",
+	"emdash": "—", "endash": "–", "bullet": "•",
+	"lquote": "‘", "rquote": "’", "ldblquote": "“", "rdblquote": "”",
+	"emspace": " ", "enspace": " ", "qmspace": " ",
+}
+
+// ExtractTextFromRTF strips RTF control words and groups, keeping the text.
+func ExtractTextFromRTF(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read RTF: %w", err)
+	}
+	if !strings.HasPrefix(string(b), "{\\rtf") {
+		return "", fmt.Errorf("failed to read RTF: missing {\\rtf header")
+	}
+	return rtfToText(b), nil
+}
+
+type rtfState struct {
+	skip bool
+	uc   int // fallback characters that follow each \uN
+}
+
+func rtfToText(b []byte) string {
+	var sb strings.Builder
+	state := rtfState{uc: 1}
+	var stack []rtfState
+	pendingSkip := 0 // fallback characters still to drop after \uN
+
+	emit := func(s string) {
+		if state.skip {
+			return
+		}
+		if pendingSkip > 0 {
+			pendingSkip--
+			return
+		}
+		sb.WriteString(s)
+	}
+
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch c {
+		case '{':
+			stack = append(stack, state)
+		case '}':
+			if n := len(stack); n > 0 {
+				state = stack[n-1]
+				stack = stack[:n-1]
+			}
+		case '\r', '\n':
+			// line breaks in the source are not text
+		case '\\':
+			if i+1 >= len(b) {
+				continue
+			}
+			i++
+			c = b[i]
+			switch {
+			case c == '\\' || c == '{' || c == '}':
+				emit(string(c))
+			case c == '~':
+				emit(" ")
+			case c == '_':
+				emit("-")
+			case c == '-':
+			case c == '*':
+				// ignorable destination we don't understand
+				state.skip = true
+			case c == '\'':
+				if i+2 < len(b) {
+					var v byte
+					if _, err := fmt.Sscanf(string(b[i+1:i+3]), "%02x", &v); err == nil {
+						// treat as Windows-1252/Latin-1, right for the common range
+						emit(string(rune(v)))
+					}
+					i += 2
+				}
+			case c == '\r' || c == '\n':
+				emit("\n")
+			case isASCIILetter(c):
+				start := i
+				for i < len(b) && isASCIILetter(b[i]) {
+					i++
+				}
+				word := string(b[start:i])
+
+				paramStart := i
+				if i < len(b) && b[i] == '-' {
+					i++
+				}
+				for i < len(b) && b[i] >= '0' && b[i] <= '9' {
+					i++
+				}
+				param, hasParam := 0, i > paramStart
+				if hasParam {
+					fmt.Sscanf(string(b[paramStart:i]), "%d", &param)
+				}
+				// a single space delimits the control word and is not text
+				if i >= len(b) || b[i] != ' ' {
+					i--
+				}
+
+				switch {
+				case rtfSkipDestinations[word]:
+					state.skip = true
+				case word == "uc" && hasParam:
+					state.uc = param
+				case word == "u" && hasParam:
+					if param < 0 {
+						param += 65536
+					}
+					emit(string(rune(param)))
+					pendingSkip = state.uc
+				default:
+					if s, ok := rtfSymbols[word]; ok {
+						emit(s)
+					}
+				}
+			}
+		default:
+			emit(string(c))
+		}
+	}
+	return sb.String()
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
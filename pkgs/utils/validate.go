@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/unidoc/unipdf/v4/model"
+)
+
+// Codes returned to clients for rejected uploads.
+const (
+	CodeEmptyFile       = "empty_file"
+	CodeFileTooLarge    = "file_too_large"
+	CodeUnsupportedType = "unsupported_media_type"
+	CodeCorruptedFile   = "corrupted_file"
+	CodeEncryptedPDF    = "encrypted_pdf"
+	CodeTooManyPages    = "too_many_pages"
+	CodeNeedsOCR        = "needs_ocr"
+)
+
+// UploadError is a rejected upload, carrying the HTTP status and a stable
+// code the client can act on.
+type UploadError struct {
+	Code    string
+	Status  int
+	Message string
+}
+
+func (e *UploadError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func uploadError(status int, code, format string, args ...any) *UploadError {
+	return &UploadError{Code: code, Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+// UploadLimits bounds what we accept; zero means unlimited.
+type UploadLimits struct {
+	MaxBytes int64
+	MaxPages int
+}
+
+// CheckSize rejects empty and oversized uploads before anything is read.
+func CheckSize(size int64, limits UploadLimits) error {
+	if size <= 0 {
+		return uploadError(http.StatusBadRequest, CodeEmptyFile, "the uploaded file is empty")
+	}
+	if limits.MaxBytes > 0 && size > limits.MaxBytes {
+		return uploadError(http.StatusRequestEntityTooLarge, CodeFileTooLarge,
+			"file is %d bytes, the limit is %d", size, limits.MaxBytes)
+	}
+	return nil
+}
+
+// ValidateUpload sniffs the real content type of the file at path and checks
+// that it is a supported, readable document within limits. filename is only
+// used to tell Markdown from plain text.
+func ValidateUpload(path, filename string, limits UploadLimits) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if err := CheckSize(info.Size(), limits); err != nil {
+		return "", err
+	}
+
+	mime, err := DetectMIME(path, filename)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := Extensions[mime]; !ok {
+		return "", uploadError(http.StatusUnsupportedMediaType, CodeUnsupportedType,
+			"%s is not a supported CV format", mime)
+	}
+
+	switch mime {
+	case MIMEPDF:
+		if err := validatePDF(path, limits); err != nil {
+			return "", err
+		}
+	case MIMEDOCX, MIMEODT:
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return "", uploadError(http.StatusUnprocessableEntity, CodeCorruptedFile, "the document cannot be opened")
+		}
+		zr.Close()
+	}
+	return mime, nil
+}
+
+func validatePDF(path string, limits UploadLimits) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader, err := model.NewPdfReader(f)
+	if err != nil {
+		return uploadError(http.StatusUnprocessableEntity, CodeCorruptedFile, "the PDF cannot be parsed")
+	}
+
+	encrypted, err := reader.IsEncrypted()
+	if err != nil {
+		return uploadError(http.StatusUnprocessableEntity, CodeCorruptedFile, "the PDF encryption dictionary is invalid")
+	}
+	if encrypted {
+		// owner-password-only PDFs open with an empty user password and are fine
+		ok, err := reader.Decrypt([]byte(""))
+		if err != nil || !ok {
+			return uploadError(http.StatusUnprocessableEntity, CodeEncryptedPDF, "the PDF is password protected")
+		}
+	}
+
+	pages, err := reader.GetNumPages()
+	if err != nil {
+		return uploadError(http.StatusUnprocessableEntity, CodeCorruptedFile, "the PDF page tree is invalid")
+	}
+	if pages == 0 {
+		return uploadError(http.StatusUnprocessableEntity, CodeCorruptedFile, "the PDF has no pages")
+	}
+	if limits.MaxPages > 0 && pages > limits.MaxPages {
+		return uploadError(http.StatusUnprocessableEntity, CodeTooManyPages,
+			"the PDF has %d pages, the limit is %d", pages, limits.MaxPages)
+	}
+	return nil
+}
+
+// AsUploadError unwraps err into an UploadError if it is one.
+func AsUploadError(err error) (*UploadError, bool) {
+	var ue *UploadError
+	if errors.As(err, &ue) {
+		return ue, true
+	}
+	return nil, false
+}
@@ -0,0 +1,143 @@
+package vectorstore
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/GazDuckington/go-gin/pkgs/stats"
+)
+
+// Memory is a VectorStore that keeps points in memory and searches by
+// comparing the query with every point, or every chunk. It is meant for development and
+// tests; nothing survives a restart.
+type Memory struct {
+	dim    int
+	mu     sync.RWMutex
+	points map[string]Point
+}
+
+// NewMemory returns an empty store for vectors of dim values.
+func NewMemory(dim int) *Memory {
+	return &Memory{dim: dim, points: map[string]Point{}}
+}
+
+func (m *Memory) Name() string { return "memory" }
+
+func (m *Memory) Upsert(_ context.Context, points ...Point) error {
+	for _, p := range points {
+		if err := CheckPoint(p, m.dim); err != nil {
+			return err
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range points {
+		p.Vector = slices.Clone(p.Vector)
+		p.Chunks = slices.Clone(p.Chunks)
+		m.points[p.ID] = p
+	}
+	return nil
+}
+
+func (m *Memory) Get(_ context.Context, ids ...string) ([]Point, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]Point, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := m.points[id]; ok {
+			p.Vector = slices.Clone(p.Vector)
+			p.Chunks = nil
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (m *Memory) Delete(_ context.Context, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.points, id)
+	}
+	return nil
+}
+
+func (m *Memory) Search(_ context.Context, q Query) ([]Hit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	vector := q.Vector
+	if q.Like != "" {
+		p, ok := m.points[q.Like]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		vector = p.Vector
+	}
+	if err := CheckDim(vector, m.dim); err != nil {
+		return nil, err
+	}
+	if q.Aggregate.Mode != "" && q.Like == "" {
+		return m.searchChunks(vector, q), nil
+	}
+
+	var hits []Hit
+	for _, p := range m.points {
+		if !q.Filter.Match(p) {
+			continue
+		}
+		score := stats.Cosine(vector, p.Vector)
+		if math.IsNaN(score) || (q.Threshold > 0 && score < q.Threshold) {
+			continue
+		}
+		p.Vector, p.Chunks = nil, nil
+		hits = append(hits, Hit{Point: p, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	if q.Offset >= len(hits) {
+		return []Hit{}, nil
+	}
+	hits = hits[q.Offset:]
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits, nil
+}
+
+// searchChunks scores every chunk and ranks the CVs by q.Aggregate; a CV
+// without chunks is scored by its CV vector as if it were its only chunk.
+func (m *Memory) searchChunks(vector []float32, q Query) []Hit {
+	var scores []ChunkScore
+	for _, p := range m.points {
+		if !q.Filter.Match(p) {
+			continue
+		}
+		chunks := p.Chunks
+		if len(chunks) == 0 {
+			chunks = []Chunk{{Vector: p.Vector}}
+		}
+		for _, c := range chunks {
+			if score := stats.Cosine(vector, c.Vector); !math.IsNaN(score) {
+				scores = append(scores, ChunkScore{ID: p.ID, Score: score})
+			}
+		}
+	}
+
+	ranked := Page(q.Aggregate.Rank(scores), q)
+	hits := make([]Hit, len(ranked))
+	for i, r := range ranked {
+		p := m.points[r.ID]
+		p.Vector, p.Chunks = nil, nil
+		hits[i] = Hit{Point: p, Score: r.Score}
+	}
+	return hits
+}
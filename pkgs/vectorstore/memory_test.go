@@ -0,0 +1,69 @@
+package vectorstore
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestMemorySearchChunks(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(2)
+	err := m.Upsert(ctx,
+		// one chunk right on the query, one orthogonal to it
+		Point{ID: "chunked", Vector: []float32{1, 1}, Chunks: []Chunk{
+			{Section: "skills", Vector: []float32{1, 0}},
+			{Section: "education", Vector: []float32{0, 1}},
+		}},
+		// stored before chunking: only a CV vector
+		Point{ID: "legacy", Vector: []float32{1, 1}},
+		Point{ID: "other", Vector: []float32{0, 1}, Chunks: []Chunk{{Section: "summary", Vector: []float32{0, 1}}}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		agg    Aggregation
+		filter Filter
+		want   []string
+		scores []float64
+	}{
+		{
+			name:   "max",
+			agg:    Aggregation{Mode: AggregateMax},
+			want:   []string{"chunked", "legacy", "other"},
+			scores: []float64{1, math.Sqrt2 / 2, 0},
+		},
+		{
+			name:   "mean of two",
+			agg:    Aggregation{Mode: AggregateMean, TopK: 2},
+			want:   []string{"legacy", "chunked", "other"},
+			scores: []float64{math.Sqrt2 / 2, 0.5, 0},
+		},
+		{
+			name:   "filtered",
+			agg:    Aggregation{Mode: AggregateMax},
+			filter: Filter{ExcludeIDs: []string{"chunked"}},
+			want:   []string{"legacy", "other"},
+			scores: []float64{math.Sqrt2 / 2, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := m.Search(ctx, Query{Vector: []float32{1, 0}, Aggregate: tt.agg, Filter: tt.filter, Limit: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(hits) != len(tt.want) {
+				t.Fatalf("got %d hits, want %d", len(hits), len(tt.want))
+			}
+			for i, h := range hits {
+				if h.ID != tt.want[i] || math.Abs(h.Score-tt.scores[i]) > 1e-6 {
+					t.Errorf("hit %d = %s %.4f, want %s %.4f", i, h.ID, h.Score, tt.want[i], tt.scores[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Package vectorstore stores CV embeddings and finds the nearest ones.
+package vectorstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when searching like a point that is not stored.
+	ErrNotFound = errors.New("point not found")
+	// ErrDimension is returned for a vector whose length is not the store's.
+	ErrDimension = errors.New("vector dimension mismatch")
+)
+
+// VectorStore keeps one vector per CV, plus one per chunk of its text, with
+// the payload searches filter on. Similarity is cosine.
+type VectorStore interface {
+	// Name is the backend, as used in VECTOR_STORE.
+	Name() string
+	// Upsert stores points with their chunks, replacing any with the same ID.
+	Upsert(ctx context.Context, points ...Point) error
+	// Get returns the stored points by ID without their chunks; IDs that are
+	// not stored are left out.
+	Get(ctx context.Context, ids ...string) ([]Point, error)
+	// Delete removes points and their chunks by ID; IDs that are not stored
+	// are ignored.
+	Delete(ctx context.Context, ids ...string) error
+	// Search returns the points nearest to the query, most similar first.
+	// Hits carry the payload but not the vector.
+	Search(ctx context.Context, q Query) ([]Hit, error)
+}
+
+// Point is a CV's vector with its payload. JobID and CreatedAt are empty
+// for points stored before they were recorded, Chunks for points stored
+// before CVs were chunked.
+type Point struct {
+	ID          string
+	Vector      []float32
+	Chunks      []Chunk
+	UserID      string
+	JobID       string
+	Title       string
+	ContentType string
+	CreatedAt   time.Time
+}
+
+// Chunk is the vector of one piece of a CV, e.g. a section.
+type Chunk struct {
+	Section string
+	Vector  []float32
+}
+
+// Query is a nearest neighbour search, either by Vector or like the stored
+// vector of point Like. With an Aggregate mode a Vector query is compared
+// with the chunks and each CV scored from its best ones; a CV without chunks
+// is compared by its CV vector instead. A zero Threshold returns every match.
+type Query struct {
+	Vector    []float32
+	Like      string
+	Aggregate Aggregation
+	Filter    Filter
+	Limit     int
+	Offset    int
+	Threshold float64
+}
+
+// Chunk aggregation modes.
+const (
+	AggregateMax  = "max"  // the best chunk
+	AggregateMean = "mean" // the mean of the TopK best chunks
+)
+
+// Aggregation scores a CV from the similarities of its chunks; an empty
+// Mode searches whole CV vectors instead.
+type Aggregation struct {
+	Mode string
+	TopK int
+}
+
+// ChunkScore is the similarity of one chunk of CV ID to the query.
+type ChunkScore struct {
+	ID    string
+	Score float64
+}
+
+// Rank scores every CV from its chunk scores, most similar first.
+func (a Aggregation) Rank(scores []ChunkScore) []ChunkScore {
+	byID := map[string][]float64{}
+	for _, s := range scores {
+		byID[s.ID] = append(byID[s.ID], s.Score)
+	}
+	k := max(a.TopK, 1)
+	ranked := make([]ChunkScore, 0, len(byID))
+	for id, s := range byID {
+		sort.Sort(sort.Reverse(sort.Float64Slice(s)))
+		score := s[0]
+		if a.Mode == AggregateMean {
+			top := s[:min(k, len(s))]
+			score = 0
+			for _, v := range top {
+				score += v
+			}
+			score /= float64(len(top))
+		}
+		ranked = append(ranked, ChunkScore{ID: id, Score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	return ranked
+}
+
+// Filter narrows a search; zero fields do not filter. Since and Until
+// leave out points without a CreatedAt.
+type Filter struct {
+	UserID        string
+	ExcludeUserID string
+	ExcludeIDs    []string
+	JobID         string
+	Since         time.Time // inclusive
+	Until         time.Time // exclusive
+}
+
+// Hit is a point found by a search with its cosine similarity.
+type Hit struct {
+	Point
+	Score float64
+}
+
+// Match reports whether p passes the filter.
+func (f Filter) Match(p Point) bool {
+	if f.UserID != "" && p.UserID != f.UserID {
+		return false
+	}
+	if f.ExcludeUserID != "" && p.UserID == f.ExcludeUserID {
+		return false
+	}
+	if f.JobID != "" && p.JobID != f.JobID {
+		return false
+	}
+	for _, id := range f.ExcludeIDs {
+		if p.ID == id {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && (p.CreatedAt.IsZero() || p.CreatedAt.Before(f.Since)) {
+		return false
+	}
+	if !f.Until.IsZero() && (p.CreatedAt.IsZero() || !p.CreatedAt.Before(f.Until)) {
+		return false
+	}
+	return true
+}
+
+// CheckDim returns ErrDimension unless v has dim values.
+func CheckDim(v []float32, dim int) error {
+	if len(v) != dim {
+		return fmt.Errorf("%w: got %d values, store has %d", ErrDimension, len(v), dim)
+	}
+	return nil
+}
+
+// CheckPoint checks the dimension of a point and of its chunks.
+func CheckPoint(p Point, dim int) error {
+	if err := CheckDim(p.Vector, dim); err != nil {
+		return err
+	}
+	for _, c := range p.Chunks {
+		if err := CheckDim(c.Vector, dim); err != nil {
+			return fmt.Errorf("chunk of %s: %w", p.ID, err)
+		}
+	}
+	return nil
+}
+
+// Page drops ranked scores below the threshold and applies offset and limit.
+func Page(ranked []ChunkScore, q Query) []ChunkScore {
+	if q.Threshold > 0 {
+		n := sort.Search(len(ranked), func(i int) bool { return ranked[i].Score < q.Threshold })
+		ranked = ranked[:n]
+	}
+	if q.Offset >= len(ranked) {
+		return nil
+	}
+	ranked = ranked[q.Offset:]
+	if q.Limit > 0 && len(ranked) > q.Limit {
+		ranked = ranked[:q.Limit]
+	}
+	return ranked
+}